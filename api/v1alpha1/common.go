@@ -22,6 +22,39 @@ type Destination struct {
 	// Type of Kubernetes Secret. Requires Create to be set to true.
 	// Defaults to Opaque.
 	Type v1.SecretType `json:"type,omitempty"`
+	// Transformation to apply to the secret data before it is synced to the Secret.
+	Transformation *Transformation `json:"transformation,omitempty"`
+	// Templates maps a key of the Secret to the Go text/template that renders its value.
+	// The templates are rendered against the Vault secret's data, available as .Secrets,
+	// and its metadata, available as .Metadata, e.g. "{{ .Secrets.username }}".
+	// The rendered keys are added after the Transformation has been applied,
+	// replacing any keys with the same name. Every key must be a valid Secret key.
+	Templates map[string]string `json:"templates,omitempty"`
+	// TemplatesConfigMap is the name of a ConfigMap in the resource's namespace whose data
+	// provides additional Templates. Templates takes precedence over the ConfigMap's data.
+	// Changes to the ConfigMap are synced immediately by a VaultStaticSecret or a
+	// VaultSecretBundle, and on the next secret rotation by a VaultDynamicSecret or a VaultPKISecret.
+	TemplatesConfigMap string `json:"templatesConfigMap,omitempty"`
+}
+
+// Transformation provides the configuration for selecting, renaming, and excluding
+// the keys of the secret data that are synced to the destination Secret.
+// Includes and Excludes are applied first, followed by Renames.
+type Transformation struct {
+	// Includes are the glob patterns of the keys to include in the Secret,
+	// e.g. "db_*". If unset, all keys are included.
+	// The patterns follow the syntax of Go's path.Match.
+	Includes []string `json:"includes,omitempty"`
+	// Excludes are the glob patterns of the keys to exclude from the Secret.
+	// A key that matches both Includes and Excludes is excluded.
+	Excludes []string `json:"excludes,omitempty"`
+	// Renames maps the name of a key to its name in the Secret.
+	// The new name must be a valid Secret key.
+	Renames map[string]string `json:"renames,omitempty"`
+	// ExcludeRaw excludes the _raw key from the Secret. The _raw key contains all of
+	// the Vault secret's data, so it is always excluded whenever any other key is excluded
+	// or renamed.
+	ExcludeRaw bool `json:"excludeRaw,omitempty"`
 }
 
 // RolloutRestartTarget provides the configuration required to perform a
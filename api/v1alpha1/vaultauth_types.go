@@ -15,8 +15,16 @@ type VaultAuthConfigKubernetes struct {
 	// Role to use for authenticating to Vault.
 	Role string `json:"role"`
 	// ServiceAccount to use when authenticating to Vault's kubernetes
-	// authentication backend.
-	ServiceAccount string `json:"serviceAccount"`
+	// authentication backend. A token is requested for it via the TokenRequest API.
+	// Either ServiceAccount or TokenFile must be set.
+	ServiceAccount string `json:"serviceAccount,omitempty"`
+	// TokenFile is the absolute path to a projected ServiceAccount token that is
+	// mounted into the operator's Pod. The file is read before every login,
+	// so rotation by the kubelet is picked up automatically.
+	// The file must be located in one of the operator's allowed token file directories.
+	// When set, ServiceAccount, TokenAudiences, and TokenExpirationSeconds are ignored,
+	// and the operator does not require permission to create ServiceAccount tokens.
+	TokenFile string `json:"tokenFile,omitempty"`
 	// TokenAudiences to include in the ServiceAccount token.
 	TokenAudiences []string `json:"audiences,omitempty"`
 	// TokenExpirationSeconds to set the ServiceAccount token.
@@ -40,9 +48,20 @@ type VaultAuthConfigJWT struct {
 	// SecretKeyRef to use when referencing the secret containing the JWT token
 	// to authenticate to Vault's JWT authentication backend.
 	SecretKeyRef *SecretKeySelector `json:"secretKeyRef,omitempty"`
+	// Wrapped denotes that the SecretKeyRef secret contains a response-wrapping token for the JWT,
+	// which will be unwrapped via sys/wrapping/unwrap before logging in. The wrapped response's data
+	// must hold the JWT in its "jwt" key. It is ignored when TokenFile or ServiceAccount is set.
+	// A wrapping token can only be unwrapped once, so a new one must be provided for every login.
+	Wrapped bool `json:"wrapped,omitempty"`
 	// ServiceAccount to use when creating a ServiceAccount token to authenticate to Vault's
 	// JWT authentication backend.
 	ServiceAccount string `json:"serviceAccount,omitempty"`
+	// TokenFile is the absolute path to a projected ServiceAccount token, or any other JWT,
+	// that is mounted into the operator's Pod. The file is read before every login,
+	// so rotation by the kubelet is picked up automatically.
+	// The file must be located in one of the operator's allowed token file directories.
+	// When set, ServiceAccount, TokenAudiences, and TokenExpirationSeconds are ignored.
+	TokenFile string `json:"tokenFile,omitempty"`
 	// TokenAudiences to include in the ServiceAccount token.
 	TokenAudiences []string `json:"audiences,omitempty"`
 	// TokenExpirationSeconds to set the ServiceAccount token.
@@ -51,6 +70,133 @@ type VaultAuthConfigJWT struct {
 	TokenExpirationSeconds int64 `json:"tokenExpirationSeconds,omitempty"`
 }
 
+// VaultAuthConfigAzure provides VaultAuth configuration options needed for authenticating to
+// Vault via an Azure AuthMethod.
+// The access token is obtained from the Azure Instance Metadata Service (IMDS) managed identity endpoint,
+// unless the Operator is configured for workload identity, in which case its federated token
+// is exchanged for an access token with Microsoft Entra ID. The workload identity client ID,
+// tenant ID, and federated token file are always taken from the AZURE_CLIENT_ID, AZURE_TENANT_ID,
+// and AZURE_FEDERATED_TOKEN_FILE environment variables that are injected into the Operator's environment.
+type VaultAuthConfigAzure struct {
+	// Role to use for authenticating to Vault.
+	Role string `json:"role"`
+	// Resource is the audience of the requested access token, it must match
+	// the resource configured on the Vault Azure auth method.
+	// Default: https://management.azure.com/
+	Resource string `json:"resource,omitempty"`
+	// ClientID of the user-assigned managed identity. It is ignored for workload identity.
+	ClientID string `json:"clientId,omitempty"`
+	// SubscriptionID of the Azure subscription the workload runs in.
+	SubscriptionID string `json:"subscriptionId,omitempty"`
+	// ResourceGroupName of the Azure resource group the workload runs in.
+	ResourceGroupName string `json:"resourceGroupName,omitempty"`
+	// VMName of the Azure virtual machine the workload runs on.
+	VMName string `json:"vmName,omitempty"`
+}
+
+// VaultAuthConfigAppRole provides VaultAuth configuration options needed for authenticating to
+// Vault via an AppRole AuthMethod.
+type VaultAuthConfigAppRole struct {
+	// RoleID of the AppRole Role to use for authenticating to Vault.
+	RoleID string `json:"roleId"`
+	// SecretKeyRef to use when referencing the secret containing the AppRole SecretID.
+	// The secret must be in the same namespace as the VaultAuth's consumer.
+	SecretKeyRef SecretKeySelector `json:"secretKeyRef"`
+	// Wrapped denotes that the secret contains a response-wrapping token for the SecretID,
+	// which will be unwrapped via sys/wrapping/unwrap before logging in.
+	// A wrapping token can only be unwrapped once, so a new one must be provided for every login.
+	Wrapped bool `json:"wrapped,omitempty"`
+}
+
+// VaultAuthConfigUserPass provides VaultAuth configuration options needed for authenticating to
+// Vault via a userpass or LDAP AuthMethod.
+type VaultAuthConfigUserPass struct {
+	// SecretRef is the name of a Kubernetes secret in the consumer's namespace which
+	// provides the username and password, under the data keys "username" and "password", respectively.
+	// The secret is typically of type "kubernetes.io/basic-auth".
+	SecretRef string `json:"secretRef"`
+}
+
+// VaultAuthConfigToken provides VaultAuth configuration options needed for authenticating to
+// Vault with a static Vault token. It is meant for development and break-glass scenarios only.
+type VaultAuthConfigToken struct {
+	// SecretKeyRef to use when referencing the secret containing the Vault token.
+	// The secret must be in the same namespace as the VaultAuth's consumer.
+	SecretKeyRef SecretKeySelector `json:"secretKeyRef"`
+	// Wrapped denotes that the secret contains a response-wrapping token for the Vault token,
+	// which will be unwrapped via sys/wrapping/unwrap before use.
+	// A wrapping token can only be unwrapped once, so a new one must be provided for every login.
+	Wrapped bool `json:"wrapped,omitempty"`
+}
+
+// VaultAuthConfigCert provides VaultAuth configuration options needed for authenticating to
+// Vault via a TLS Certificate AuthMethod.
+type VaultAuthConfigCert struct {
+	// Role is the name of the certificate role to authenticate against.
+	// If not set, Vault will try all certificate roles and return any one that matches.
+	Role string `json:"role,omitempty"`
+	// SecretRef is the name of a Kubernetes secret of type "kubernetes.io/tls"
+	// that contains the client certificate and private key, under the data keys
+	// "tls.crt" and "tls.key", respectively.
+	// The secret must be in the same namespace as the VaultAuth's consumer.
+	SecretRef string `json:"secretRef"`
+}
+
+// VaultAuthConfigAWS provides VaultAuth configuration options needed for authenticating to
+// Vault via an AWS AuthMethod, using the IAM auth type.
+// The AWS credentials are taken from SecretRef if it is set, otherwise they are obtained by
+// exchanging an IRSA web identity token for temporary credentials.
+type VaultAuthConfigAWS struct {
+	// Role to use for authenticating to Vault.
+	Role string `json:"role"`
+	// Region is the AWS region to use when signing the sts:GetCallerIdentity request.
+	// It must match the STS region configured on the Vault AWS auth method.
+	// Default: us-east-1
+	Region string `json:"region,omitempty"`
+	// STSEndpoint is the URL of the AWS STS endpoint to use for the signed request,
+	// and for exchanging the web identity token. It must be an https AWS STS endpoint,
+	// or one of the operator's allowed STS endpoints.
+	// Default: the regional STS endpoint of Region, or https://sts.amazonaws.com for us-east-1.
+	STSEndpoint string `json:"stsEndpoint,omitempty"`
+	// HeaderValue to set in the X-Vault-AWS-IAM-Server-ID header of the signed request.
+	// It must match the iam_server_id_header_value configured on the Vault AWS auth method.
+	HeaderValue string `json:"headerValue,omitempty"`
+	// SecretRef is the name of a Kubernetes secret in the consumer's namespace which
+	// provides the AWS static credentials. The secret must have the data keys
+	// "access_key_id" and "secret_access_key", and may have the key "session_token".
+	SecretRef string `json:"secretRef,omitempty"`
+	// IRSAConfig is used when SecretRef is not set. The IAM role and the web identity
+	// token file are always taken from the AWS_ROLE_ARN and AWS_WEB_IDENTITY_TOKEN_FILE
+	// environment variables that EKS injects into the Operator's environment.
+	IRSAConfig *AWSIRSAConfig `json:"irsaConfig,omitempty"`
+}
+
+// AWSIRSAConfig provides the configuration for obtaining AWS credentials from an
+// IRSA (IAM Roles for Service Accounts) web identity token.
+type AWSIRSAConfig struct {
+	// SessionName to use when assuming the IAM role.
+	// Default: vault-secrets-operator
+	SessionName string `json:"sessionName,omitempty"`
+}
+
+// VaultAuthConfigGCP provides VaultAuth configuration options needed for authenticating to
+// Vault via a GCP AuthMethod, using the IAM auth type.
+// The signed JWT is produced from the service account key in SecretKeyRef if it is set,
+// otherwise the Workload Identity service account, obtained from the metadata server, is used
+// to sign it via the IAM Credentials API. The metadata server and IAM Credentials API endpoints
+// are configured on the Operator, since the Operator's access token is sent to them.
+type VaultAuthConfigGCP struct {
+	// Role to use for authenticating to Vault.
+	Role string `json:"role"`
+	// SecretKeyRef to use when referencing the secret containing the GCP service account
+	// key JSON. The secret must be in the same namespace as the VaultAuth's consumer.
+	SecretKeyRef *SecretKeySelector `json:"secretKeyRef,omitempty"`
+	// WorkloadIdentityServiceAccount is the email of the GCP service account to authenticate as
+	// when SecretKeyRef is not set.
+	// Default: the metadata server's default service account.
+	WorkloadIdentityServiceAccount string `json:"workloadIdentityServiceAccount,omitempty"`
+}
+
 // VaultAuthSpec defines the desired state of VaultAuth
 type VaultAuthSpec struct {
 	// VaultConnectionRef of the corresponding VaultConnection CustomResource.
@@ -60,18 +206,44 @@ type VaultAuthSpec struct {
 	// Namespace to auth to in Vault
 	Namespace string `json:"namespace,omitempty"`
 	// Method to use when authenticating to Vault.
-	// +kubebuilder:validation:Enum=kubernetes;jwt
+	// The autoAuth method skips logging in to Vault, it requires that the VaultConnection's address be a
+	// Vault Agent or Vault Proxy that injects its own auto-auth token, i.e. with use_auto_auth_token enabled.
+	// +kubebuilder:validation:Enum=kubernetes;jwt;appRole;cert;aws;gcp;azure;token;userpass;ldap;autoAuth
 	Method string `json:"method"`
 	// Mount to use when authenticating to auth method.
+	// It is ignored when the Method is set to token or autoAuth.
 	Mount string `json:"mount"`
 	// Params to use when authenticating to Vault
 	Params map[string]string `json:"params,omitempty"`
-	// Headers to be included in all Vault requests.
+	// Headers to be included in the Vault login request.
+	// Use the VaultConnection's Headers for headers that must be included in all Vault requests.
 	Headers map[string]string `json:"headers,omitempty"`
+	// HeadersFrom are Secret keys whose values are included as headers in the Vault login request,
+	// the key is used as the header name, and takes precedence over Headers. The Secrets must be in
+	// the VaultAuth's namespace. All cached Vault clients for the VaultAuth are rebuilt whenever one
+	// of the Secrets changes. Use the VaultConnection's HeadersFrom for headers that must be included
+	// in all Vault requests.
+	HeadersFrom []SecretKeySelector `json:"headersFrom,omitempty"`
 	// Kubernetes specific auth configuration, requires that the Method be set to kubernetes.
 	Kubernetes *VaultAuthConfigKubernetes `json:"kubernetes,omitempty"`
 	// JWT specific auth configuration, requires that the Method be set to jwt.
 	JWT *VaultAuthConfigJWT `json:"jwt,omitempty"`
+	// Azure specific auth configuration, requires that the Method be set to azure.
+	Azure *VaultAuthConfigAzure `json:"azure,omitempty"`
+	// AppRole specific auth configuration, requires that the Method be set to appRole.
+	AppRole *VaultAuthConfigAppRole `json:"appRole,omitempty"`
+	// Token specific auth configuration, requires that the Method be set to token.
+	Token *VaultAuthConfigToken `json:"token,omitempty"`
+	// UserPass specific auth configuration, requires that the Method be set to userpass.
+	UserPass *VaultAuthConfigUserPass `json:"userpass,omitempty"`
+	// LDAP specific auth configuration, requires that the Method be set to ldap.
+	LDAP *VaultAuthConfigUserPass `json:"ldap,omitempty"`
+	// Cert specific auth configuration, requires that the Method be set to cert.
+	Cert *VaultAuthConfigCert `json:"cert,omitempty"`
+	// AWS specific auth configuration, requires that the Method be set to aws.
+	AWS *VaultAuthConfigAWS `json:"aws,omitempty"`
+	// GCP specific auth configuration, requires that the Method be set to gcp.
+	GCP *VaultAuthConfigGCP `json:"gcp,omitempty"`
 	// StorageEncryption provides the necessary configuration to encrypt the client storage cache.
 	// This should only be configured when client cache persistence with encryption is enabled.
 	// This is done by passing setting the manager's commandline argument --client-cache-persistence-model=direct-encrypted
@@ -85,6 +257,13 @@ type VaultAuthStatus struct {
 	// Valid auth mechanism.
 	Valid bool   `json:"valid"`
 	Error string `json:"error"`
+	// HeadersSecretVersions are the resourceVersions of the HeadersFrom Secrets
+	// that were last observed by the operator, keyed by Secret name.
+	HeadersSecretVersions map[string]string `json:"headersSecretVersions,omitempty"`
+	// Conditions of the VaultAuth, Degraded is True when the referenced VaultConnection is unhealthy.
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
 //+kubebuilder:object:root=true
@@ -12,22 +12,133 @@ import (
 
 // VaultConnectionSpec defines the desired state of VaultConnection
 type VaultConnectionSpec struct {
-	// Address of the Vault server
-	Address string `json:"address"`
+	// Address of the Vault server.
+	// Either Address or Addresses must be set, when both are set Address is always preferred.
+	// A unix:// address is the path of the unix socket of a Vault Agent or Vault Proxy listener,
+	// e.g. unix:///var/run/vault/proxy.sock, it cannot be failed over or used with ProxyURL.
+	Address string `json:"address,omitempty"`
+	// Addresses of the Vault servers to fail over between. Requests are sent to the
+	// most preferred healthy address, an address is considered unhealthy when its
+	// connection drops, or when sys/health reports it as being sealed or in standby.
+	// Addresses are preferred in order of descending Weight, then in list order.
+	Addresses []VaultConnectionAddress `json:"addresses,omitempty"`
 	// Headers to be included in all Vault requests.
 	Headers map[string]string `json:"headers,omitempty"`
+	// HeadersFrom are Secret keys whose values are included as headers in all Vault requests,
+	// the key is used as the header name. The Secrets must be in the VaultConnection's namespace.
+	// All cached Vault clients for the VaultConnection are rebuilt whenever one of the Secrets changes.
+	HeadersFrom []SecretKeySelector `json:"headersFrom,omitempty"`
 	// TLSServerName to use as the SNI host for TLS connections.
 	TLSServerName string `json:"tlsServerName,omitempty"`
 	// CACertSecretRef containing the trusted PEM encoded CA certificate chain.
 	CACertSecretRef string `json:"caCertSecretRef,omitempty"`
+	// CACertConfigMapRef is the name of a ConfigMap containing the trusted PEM encoded CA certificate chain,
+	// e.g. a ConfigMap that is distributed by a trust-manager Bundle.
+	// When both CACertSecretRef and CACertConfigMapRef are set, the certificates from both are trusted.
+	// All cached Vault clients for the VaultConnection are rebuilt whenever the ConfigMap changes.
+	CACertConfigMapRef string `json:"caCertConfigMapRef,omitempty"`
+	// CACertConfigMapKey is the key in the CACertConfigMapRef ConfigMap that holds the CA certificate chain.
+	// Defaults to ca.crt.
+	CACertConfigMapKey string `json:"caCertConfigMapKey,omitempty"`
 	// SkipTLSVerify for TLS connections.
 	SkipTLSVerify bool `json:"skipTLSVerify,omitempty"`
+	// ClientCertSecretRef is the name of a kubernetes.io/tls Secret in the VaultConnection's namespace,
+	// containing the PEM encoded client certificate and private key that are presented to Vault
+	// during the TLS handshake. This is required when Vault's listener is configured with
+	// tls_require_and_verify_client_cert.
+	// It is ignored by VaultAuths that use the cert method, since they provide their own client certificate.
+	// All cached Vault clients for the VaultConnection are rebuilt whenever the Secret changes.
+	ClientCertSecretRef string `json:"clientCertSecretRef,omitempty"`
+	// ProxyURL of the HTTP proxy that all Vault requests are sent through, e.g. http://proxy.example.com:3128.
+	// HTTPS requests are tunneled through the proxy with HTTP CONNECT.
+	// When set, the operator's HTTP_PROXY, HTTPS_PROXY, and NO_PROXY environment variables are ignored.
+	ProxyURL string `json:"proxyURL,omitempty"`
+	// NoProxy is a list of hosts, domains, IP addresses, or CIDRs that are connected to directly,
+	// bypassing ProxyURL. Follows the same format as the NO_PROXY environment variable.
+	NoProxy []string `json:"noProxy,omitempty"`
+	// ProxySecretRef is the name of a kubernetes.io/basic-auth Secret in the VaultConnection's namespace,
+	// containing the username and password used to authenticate to ProxyURL.
+	// All cached Vault clients for the VaultConnection are rebuilt whenever the Secret changes.
+	ProxySecretRef string `json:"proxySecretRef,omitempty"`
+	// Timeout of every Vault request, in duration notation e.g. 30s. Defaults to 60s.
+	Timeout string `json:"timeout,omitempty"`
+	// MaxRetries of Vault requests that failed with a retryable error, e.g. a 5xx response.
+	// Set to 0 to disable retries. Defaults to 2.
+	// +kubebuilder:validation:Minimum=0
+	MaxRetries *int `json:"maxRetries,omitempty"`
+	// MinRetryWait is the minimum amount of time to wait before retrying a request,
+	// in duration notation e.g. 1s. Defaults to 1s.
+	MinRetryWait string `json:"minRetryWait,omitempty"`
+	// MaxRetryWait is the maximum amount of time to wait before retrying a request,
+	// in duration notation e.g. 1m. Defaults to 1.5s.
+	MaxRetryWait string `json:"maxRetryWait,omitempty"`
+	// HealthCheckInterval is the period of time between Vault health checks, in duration notation e.g. 30s.
+	// The health of every address is recorded in the VaultConnection's status conditions.
+	// Set to 0s to only check the health when the VaultConnection changes. Defaults to 1m.
+	HealthCheckInterval string `json:"healthCheckInterval,omitempty"`
+	// ReadConsistency of the requests sent to Vault Enterprise performance standbys.
+	// With eventual, standbys serve reads from their local state, which may be stale.
+	// With readYourWrites, every request carries the X-Vault-Index of the client's last write,
+	// e.g. its login, and a standby that has not caught up responds with a 412, which is retried
+	// according to MaxRetries. With forwardInconsistent, such a standby forwards the request to
+	// the active node instead, by way of the X-Vault-Inconsistent header. Defaults to eventual.
+	// When set, performance standbys are considered healthy, so that reads can be spread across them.
+	// +kubebuilder:validation:Enum=eventual;readYourWrites;forwardInconsistent
+	ReadConsistency string `json:"readConsistency,omitempty"`
+	// RateLimit of the requests sent to Vault, shared by all the VaultConnection's Vault clients.
+	// Requests that exceed the limit wait until they are allowed to be sent.
+	RateLimit *VaultConnectionRateLimit `json:"rateLimit,omitempty"`
+	// MaxInFlight is the maximum number of concurrent requests sent to Vault, shared by all the
+	// VaultConnection's Vault clients. Requests that exceed the limit wait for an in-flight request
+	// to complete. Defaults to 0, which does not limit the number of concurrent requests.
+	// +kubebuilder:validation:Minimum=0
+	MaxInFlight int `json:"maxInFlight,omitempty"`
+}
+
+// VaultConnectionRateLimit configures the client-side rate limiting of Vault requests.
+type VaultConnectionRateLimit struct {
+	// QPS is the maximum sustained number of requests per second.
+	// +kubebuilder:validation:Minimum=1
+	QPS int `json:"qps"`
+	// Burst is the maximum number of requests that can be sent at once. Defaults to QPS.
+	// +kubebuilder:validation:Minimum=0
+	Burst int `json:"burst,omitempty"`
+}
+
+// VaultConnectionAddress is a Vault server address that can be failed over to.
+type VaultConnectionAddress struct {
+	// Address of the Vault server.
+	Address string `json:"address"`
+	// Weight of the address, addresses with a higher weight are preferred.
+	// +kubebuilder:validation:Minimum=0
+	Weight int `json:"weight,omitempty"`
 }
 
 // VaultConnectionStatus defines the observed state of VaultConnection
 type VaultConnectionStatus struct {
 	// Valid auth mechanism.
 	Valid bool `json:"valid"`
+	// ActiveAddress is the most preferred Vault address that was found to be healthy.
+	ActiveAddress string `json:"activeAddress,omitempty"`
+	// ClientCertSecretVersion is the resourceVersion of the ClientCertSecretRef Secret
+	// that was last observed by the operator.
+	ClientCertSecretVersion string `json:"clientCertSecretVersion,omitempty"`
+	// CACertConfigMapVersion is the resourceVersion of the CACertConfigMapRef ConfigMap
+	// that was last observed by the operator.
+	CACertConfigMapVersion string `json:"caCertConfigMapVersion,omitempty"`
+	// ProxySecretVersion is the resourceVersion of the ProxySecretRef Secret
+	// that was last observed by the operator.
+	ProxySecretVersion string `json:"proxySecretVersion,omitempty"`
+	// HeadersSecretVersions are the resourceVersions of the HeadersFrom Secrets
+	// that were last observed by the operator, keyed by Secret name.
+	HeadersSecretVersions map[string]string `json:"headersSecretVersions,omitempty"`
+	// Conditions of the Vault server at ActiveAddress, or of the first reachable address when there is
+	// no active address. Healthy is True when Vault is initialized, unsealed and active, Sealed and Standby
+	// report Vault's seal and HA status. The condition messages include Vault's version, replication modes
+	// and the latency of the last health check.
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
 //+kubebuilder:object:root=true
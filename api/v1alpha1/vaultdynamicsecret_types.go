@@ -41,6 +41,14 @@ type VaultDynamicSecretStatus struct {
 	// LastRuntimePodUID used for tracking the transition from one Pod to the next.
 	// It is used to mitigate the effects of a Vault lease renewal storm.
 	LastRuntimePodUID types.UID `json:"lastRuntimePodUID,omitempty"`
+	// ControlGroupAccessor of the Vault control group request that is awaiting
+	// authorization. The secret will be synced once the request has been authorized.
+	ControlGroupAccessor string `json:"controlGroupAccessor,omitempty"`
+	// Conditions of the VaultDynamicSecret, TemplatesRendered reports whether the Destination's
+	// templates were rendered successfully.
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
 type VaultSecretLease struct {
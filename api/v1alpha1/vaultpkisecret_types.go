@@ -115,6 +115,14 @@ type VaultPKISecretStatus struct {
 	Expiration   int64  `json:"expiration,omitempty"`
 	Valid        bool   `json:"valid"`
 	Error        string `json:"error"`
+	// ControlGroupAccessor of the Vault control group request that is awaiting
+	// authorization. The secret will be synced once the request has been authorized.
+	ControlGroupAccessor string `json:"controlGroupAccessor,omitempty"`
+	// Conditions of the VaultPKISecret, TemplatesRendered reports whether the Destination's
+	// templates were rendered successfully.
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
 //+kubebuilder:object:root=true
@@ -0,0 +1,130 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// VaultSecretBundleSpec defines the desired state of VaultSecretBundle
+type VaultSecretBundleSpec struct {
+	// VaultAuthRef of the VaultAuth resource
+	// If no value is specified the Operator will default to the `default` VaultAuth,
+	// configured in its own Kubernetes namespace.
+	VaultAuthRef string `json:"vaultAuthRef,omitempty"`
+	// Namespace to get the secrets from in Vault
+	Namespace string `json:"namespace,omitempty"`
+	// Sources of the Vault secrets that are merged into the Destination Secret.
+	// Each source is refreshed on its own schedule.
+	// +kubebuilder:validation:MinItems=1
+	Sources []VaultSecretBundleSource `json:"sources"`
+	// ConflictPolicy determines how a key that is provided by more than one source is handled.
+	// With "error" the sync fails, with "firstWins" the value of the first source in Sources is kept,
+	// and with "lastWins" the value of the last source in Sources is kept.
+	// +kubebuilder:validation:Enum={error,firstWins,lastWins}
+	// +kubebuilder:default=error
+	ConflictPolicy string `json:"conflictPolicy,omitempty"`
+	// Revoke the lease of a dynamic source when it is rotated, when the source is removed,
+	// or on VaultSecretBundle deletion.
+	Revoke bool `json:"revoke,omitempty"`
+	// RolloutRestartTargets should be configured whenever the application(s) consuming the Vault secret does
+	// not support dynamically reloading a rotated secret.
+	// In that case one, or more RolloutRestartTarget(s) can be configured here. The Operator will
+	// trigger a "rollout-restart" for each target whenever the Vault secret changes between reconciliation events.
+	// See RolloutRestartTarget for more details.
+	RolloutRestartTargets []RolloutRestartTarget `json:"rolloutRestartTargets,omitempty"`
+	// Destination provides configuration necessary for syncing the Vault secrets to Kubernetes.
+	// Its templates are rendered against the merged data of all sources, available as .Secrets,
+	// and must not render a key that is provided by any of the sources. The metadata of each
+	// source is available as .Metadata, keyed by the source's name, e.g. "{{ .Metadata.db.lease_id }}".
+	// It includes the lease of a dynamic source, and the version of a kv-v2 source.
+	Destination Destination `json:"destination"`
+}
+
+// VaultSecretBundleSource is a single Vault secret of a VaultSecretBundle.
+type VaultSecretBundleSource struct {
+	// Name of the source, must be unique within the VaultSecretBundle.
+	Name string `json:"name"`
+	// Type of the Vault secrets engine.
+	// +kubebuilder:validation:Enum={kv-v1,kv-v2,dynamic,pki}
+	Type string `json:"type"`
+	// Mount for the secret in Vault
+	Mount string `json:"mount"`
+	// Path of the secret in Vault, relative to the Mount.
+	// For kv-v1 and kv-v2 it is the secret's name, e.g. "app/config".
+	// For dynamic it is the path of the credentials, e.g. "creds/my-role".
+	// For pki it is the role that is used for issuing the certificate, e.g. "my-role".
+	Path string `json:"path"`
+	// Params to include in the request to Vault. Only valid for the dynamic and pki types.
+	// For dynamic the credentials are requested with a write instead of a read when set.
+	// For pki these are the parameters of the issue request, e.g. "common_name".
+	Params map[string]string `json:"params,omitempty"`
+	// KeyPrefix is prepended to each of the source's keys in the Destination Secret.
+	KeyPrefix string `json:"keyPrefix,omitempty"`
+	// RefreshAfter a period of time, in duration notation. Only valid for the kv-v1 and kv-v2 types.
+	// When unset, the secret is only synced when it is missing from the Destination Secret,
+	// or when the VaultSecretBundle has been updated.
+	// Dynamic secrets are refreshed according to their lease, and pki certificates according
+	// to their expiration.
+	RefreshAfter string `json:"refreshAfter,omitempty"`
+}
+
+// VaultSecretBundleStatus defines the observed state of VaultSecretBundle
+type VaultSecretBundleStatus struct {
+	// ObservedGeneration of the VaultSecretBundle when its sources were last synced.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// Sources provides the status of each of the VaultSecretBundle's sources.
+	Sources []VaultSecretBundleSourceStatus `json:"sources,omitempty"`
+	// Conditions of the VaultSecretBundle, TemplatesRendered reports whether the Destination's
+	// templates were rendered successfully.
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// VaultSecretBundleSourceStatus provides the observed state of a VaultSecretBundleSource.
+type VaultSecretBundleSourceStatus struct {
+	// Name of the source.
+	Name string `json:"name"`
+	// Keys of the Destination Secret that were synced from the source.
+	Keys []string `json:"keys,omitempty"`
+	// LastSyncTime of the source's secret, in unix seconds.
+	LastSyncTime int64 `json:"lastSyncTime,omitempty"`
+	// NextSyncTime of the source's secret, in unix seconds.
+	// For dynamic secrets the lease renewal is attempted at this time.
+	// Unset if the source is never refreshed.
+	NextSyncTime int64 `json:"nextSyncTime,omitempty"`
+	// SecretLease of a dynamic source's secret.
+	SecretLease *VaultSecretLease `json:"secretLease,omitempty"`
+	// Version of a kv-v2 source's secret.
+	Version int `json:"version,omitempty"`
+	// ControlGroupAccessor of the Vault control group request for the source's secret that is
+	// awaiting authorization. The source will be synced once the request has been authorized.
+	ControlGroupAccessor string `json:"controlGroupAccessor,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+
+// VaultSecretBundle is the Schema for the vaultsecretbundles API
+type VaultSecretBundle struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   VaultSecretBundleSpec   `json:"spec,omitempty"`
+	Status VaultSecretBundleStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// VaultSecretBundleList contains a list of VaultSecretBundle
+type VaultSecretBundleList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []VaultSecretBundle `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&VaultSecretBundle{}, &VaultSecretBundleList{})
+}
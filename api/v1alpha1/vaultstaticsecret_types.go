@@ -22,9 +22,22 @@ type VaultStaticSecretSpec struct {
 	Mount string `json:"mount"`
 	// Name of the secret in Vault
 	Name string `json:"name"`
+	// Prefix configures the syncing of all secrets found under Name. When set, Name is
+	// treated as a path prefix that is listed recursively, instead of a single secret.
+	Prefix *VaultStaticSecretPrefix `json:"prefix,omitempty"`
 	// Type of the Vault static secret
 	// +kubebuilder:validation:Enum={kv-v1,kv-v2}
 	Type string `json:"type"`
+	// Version of the secret to fetch. Only valid for type kv-v2. When unset, the latest
+	// version of the secret is synced. Pinning a version allows for rolling back to a
+	// previous version of the secret, without making any changes in Vault.
+	// +kubebuilder:validation:Minimum=0
+	Version int `json:"version,omitempty"`
+	// SyncCustomMetadata copies the secret's custom_metadata to the Destination
+	// Secret's annotations. Only valid for type kv-v2. Any annotations configured in
+	// Destination.Annotations take precedence, and custom_metadata keys that are not valid
+	// annotation keys are skipped. Requires Destination.Create to be set to true.
+	SyncCustomMetadata bool `json:"syncCustomMetadata,omitempty"`
 	// RefreshAfter a period of time, in duration notation
 	RefreshAfter string `json:"refreshAfter,omitempty"`
 	// HMACSecretData determines whether the Operator computes the
@@ -45,6 +58,26 @@ type VaultStaticSecretSpec struct {
 	Destination Destination `json:"destination"`
 }
 
+// VaultStaticSecretPrefix configures the syncing of all secrets found under a Vault path prefix.
+type VaultStaticSecretPrefix struct {
+	// Depth of the recursive listing of the path prefix. A Depth of 1 only
+	// includes the secrets directly under the prefix.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default=1
+	Depth int `json:"depth,omitempty"`
+	// Mode of syncing the secrets found under the path prefix.
+	// With "merged", all secrets are synced to the Destination Secret, each key is prefixed with
+	// the secret's path relative to the prefix, with "/" replaced by "_". E.g. the key "password"
+	// of the secret "db/admin" becomes "db_admin_password".
+	// With "perLeaf", each secret is synced to its own Secret, named after the Destination's Name
+	// and the secret's relative path. E.g. the secret "db/admin" is synced to "<name>-db-admin".
+	// Requires Destination.Create to be set to true.
+	// In either mode, secrets that no longer exist in Vault are removed from Kubernetes.
+	// +kubebuilder:validation:Enum={merged,perLeaf}
+	// +kubebuilder:default=merged
+	Mode string `json:"mode,omitempty"`
+}
+
 // VaultStaticSecretStatus defines the observed state of VaultStaticSecret
 type VaultStaticSecretStatus struct {
 	// SecretMAC used when deciding whether new Vault secret data should be synced.
@@ -55,6 +88,28 @@ type VaultStaticSecretStatus struct {
 	// The SecretMac is also used to detect drift in the Destination Secret's Data.
 	// If drift is detected the data will be synced to the Destination.
 	SecretMAC string `json:"secretMAC,omitempty"`
+	// SecretVersion of the last synced kv-v2 secret.
+	SecretVersion *VaultSecretVersion `json:"secretVersion,omitempty"`
+	// ControlGroupAccessor of the Vault control group request that is awaiting
+	// authorization. The secret will be synced once the request has been authorized.
+	ControlGroupAccessor string `json:"controlGroupAccessor,omitempty"`
+	// Conditions of the VaultStaticSecret, TemplatesRendered reports whether the Destination's
+	// templates were rendered successfully.
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// VaultSecretVersion provides the metadata of a kv-v2 secret version.
+type VaultSecretVersion struct {
+	// Version of the Vault secret.
+	Version int `json:"version"`
+	// CreatedTime of the Vault secret version.
+	CreatedTime metav1.Time `json:"createdTime,omitempty"`
+	// DeletionTime of the Vault secret version, only set if the version was deleted.
+	DeletionTime *metav1.Time `json:"deletionTime,omitempty"`
+	// Destroyed is true if the Vault secret version was permanently destroyed.
+	Destroyed bool `json:"destroyed,omitempty"`
 }
 
 //+kubebuilder:object:root=true
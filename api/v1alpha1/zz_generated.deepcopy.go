@@ -9,9 +9,25 @@
 package v1alpha1
 
 import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AWSIRSAConfig) DeepCopyInto(out *AWSIRSAConfig) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AWSIRSAConfig.
+func (in *AWSIRSAConfig) DeepCopy() *AWSIRSAConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(AWSIRSAConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Destination) DeepCopyInto(out *Destination) {
 	*out = *in
@@ -29,6 +45,18 @@ func (in *Destination) DeepCopyInto(out *Destination) {
 			(*out)[key] = val
 		}
 	}
+	if in.Transformation != nil {
+		in, out := &in.Transformation, &out.Transformation
+		*out = new(Transformation)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Templates != nil {
+		in, out := &in.Templates, &out.Templates
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Destination.
@@ -86,13 +114,45 @@ func (in *StorageEncryption) DeepCopy() *StorageEncryption {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Transformation) DeepCopyInto(out *Transformation) {
+	*out = *in
+	if in.Includes != nil {
+		in, out := &in.Includes, &out.Includes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Excludes != nil {
+		in, out := &in.Excludes, &out.Excludes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Renames != nil {
+		in, out := &in.Renames, &out.Renames
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Transformation.
+func (in *Transformation) DeepCopy() *Transformation {
+	if in == nil {
+		return nil
+	}
+	out := new(Transformation)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VaultAuth) DeepCopyInto(out *VaultAuth) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VaultAuth.
@@ -113,6 +173,92 @@ func (in *VaultAuth) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VaultAuthConfigAWS) DeepCopyInto(out *VaultAuthConfigAWS) {
+	*out = *in
+	if in.IRSAConfig != nil {
+		in, out := &in.IRSAConfig, &out.IRSAConfig
+		*out = new(AWSIRSAConfig)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VaultAuthConfigAWS.
+func (in *VaultAuthConfigAWS) DeepCopy() *VaultAuthConfigAWS {
+	if in == nil {
+		return nil
+	}
+	out := new(VaultAuthConfigAWS)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VaultAuthConfigAppRole) DeepCopyInto(out *VaultAuthConfigAppRole) {
+	*out = *in
+	out.SecretKeyRef = in.SecretKeyRef
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VaultAuthConfigAppRole.
+func (in *VaultAuthConfigAppRole) DeepCopy() *VaultAuthConfigAppRole {
+	if in == nil {
+		return nil
+	}
+	out := new(VaultAuthConfigAppRole)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VaultAuthConfigAzure) DeepCopyInto(out *VaultAuthConfigAzure) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VaultAuthConfigAzure.
+func (in *VaultAuthConfigAzure) DeepCopy() *VaultAuthConfigAzure {
+	if in == nil {
+		return nil
+	}
+	out := new(VaultAuthConfigAzure)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VaultAuthConfigCert) DeepCopyInto(out *VaultAuthConfigCert) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VaultAuthConfigCert.
+func (in *VaultAuthConfigCert) DeepCopy() *VaultAuthConfigCert {
+	if in == nil {
+		return nil
+	}
+	out := new(VaultAuthConfigCert)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VaultAuthConfigGCP) DeepCopyInto(out *VaultAuthConfigGCP) {
+	*out = *in
+	if in.SecretKeyRef != nil {
+		in, out := &in.SecretKeyRef, &out.SecretKeyRef
+		*out = new(SecretKeySelector)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VaultAuthConfigGCP.
+func (in *VaultAuthConfigGCP) DeepCopy() *VaultAuthConfigGCP {
+	if in == nil {
+		return nil
+	}
+	out := new(VaultAuthConfigGCP)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VaultAuthConfigJWT) DeepCopyInto(out *VaultAuthConfigJWT) {
 	*out = *in
@@ -158,6 +304,37 @@ func (in *VaultAuthConfigKubernetes) DeepCopy() *VaultAuthConfigKubernetes {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VaultAuthConfigToken) DeepCopyInto(out *VaultAuthConfigToken) {
+	*out = *in
+	out.SecretKeyRef = in.SecretKeyRef
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VaultAuthConfigToken.
+func (in *VaultAuthConfigToken) DeepCopy() *VaultAuthConfigToken {
+	if in == nil {
+		return nil
+	}
+	out := new(VaultAuthConfigToken)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VaultAuthConfigUserPass) DeepCopyInto(out *VaultAuthConfigUserPass) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VaultAuthConfigUserPass.
+func (in *VaultAuthConfigUserPass) DeepCopy() *VaultAuthConfigUserPass {
+	if in == nil {
+		return nil
+	}
+	out := new(VaultAuthConfigUserPass)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VaultAuthList) DeepCopyInto(out *VaultAuthList) {
 	*out = *in
@@ -207,6 +384,11 @@ func (in *VaultAuthSpec) DeepCopyInto(out *VaultAuthSpec) {
 			(*out)[key] = val
 		}
 	}
+	if in.HeadersFrom != nil {
+		in, out := &in.HeadersFrom, &out.HeadersFrom
+		*out = make([]SecretKeySelector, len(*in))
+		copy(*out, *in)
+	}
 	if in.Kubernetes != nil {
 		in, out := &in.Kubernetes, &out.Kubernetes
 		*out = new(VaultAuthConfigKubernetes)
@@ -217,6 +399,46 @@ func (in *VaultAuthSpec) DeepCopyInto(out *VaultAuthSpec) {
 		*out = new(VaultAuthConfigJWT)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Azure != nil {
+		in, out := &in.Azure, &out.Azure
+		*out = new(VaultAuthConfigAzure)
+		**out = **in
+	}
+	if in.AppRole != nil {
+		in, out := &in.AppRole, &out.AppRole
+		*out = new(VaultAuthConfigAppRole)
+		**out = **in
+	}
+	if in.Token != nil {
+		in, out := &in.Token, &out.Token
+		*out = new(VaultAuthConfigToken)
+		**out = **in
+	}
+	if in.UserPass != nil {
+		in, out := &in.UserPass, &out.UserPass
+		*out = new(VaultAuthConfigUserPass)
+		**out = **in
+	}
+	if in.LDAP != nil {
+		in, out := &in.LDAP, &out.LDAP
+		*out = new(VaultAuthConfigUserPass)
+		**out = **in
+	}
+	if in.Cert != nil {
+		in, out := &in.Cert, &out.Cert
+		*out = new(VaultAuthConfigCert)
+		**out = **in
+	}
+	if in.AWS != nil {
+		in, out := &in.AWS, &out.AWS
+		*out = new(VaultAuthConfigAWS)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.GCP != nil {
+		in, out := &in.GCP, &out.GCP
+		*out = new(VaultAuthConfigGCP)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.StorageEncryption != nil {
 		in, out := &in.StorageEncryption, &out.StorageEncryption
 		*out = new(StorageEncryption)
@@ -237,6 +459,20 @@ func (in *VaultAuthSpec) DeepCopy() *VaultAuthSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VaultAuthStatus) DeepCopyInto(out *VaultAuthStatus) {
 	*out = *in
+	if in.HeadersSecretVersions != nil {
+		in, out := &in.HeadersSecretVersions, &out.HeadersSecretVersions
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VaultAuthStatus.
@@ -255,7 +491,7 @@ func (in *VaultConnection) DeepCopyInto(out *VaultConnection) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VaultConnection.
@@ -276,6 +512,21 @@ func (in *VaultConnection) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VaultConnectionAddress) DeepCopyInto(out *VaultConnectionAddress) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VaultConnectionAddress.
+func (in *VaultConnectionAddress) DeepCopy() *VaultConnectionAddress {
+	if in == nil {
+		return nil
+	}
+	out := new(VaultConnectionAddress)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VaultConnectionList) DeepCopyInto(out *VaultConnectionList) {
 	*out = *in
@@ -308,9 +559,29 @@ func (in *VaultConnectionList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VaultConnectionRateLimit) DeepCopyInto(out *VaultConnectionRateLimit) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VaultConnectionRateLimit.
+func (in *VaultConnectionRateLimit) DeepCopy() *VaultConnectionRateLimit {
+	if in == nil {
+		return nil
+	}
+	out := new(VaultConnectionRateLimit)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VaultConnectionSpec) DeepCopyInto(out *VaultConnectionSpec) {
 	*out = *in
+	if in.Addresses != nil {
+		in, out := &in.Addresses, &out.Addresses
+		*out = make([]VaultConnectionAddress, len(*in))
+		copy(*out, *in)
+	}
 	if in.Headers != nil {
 		in, out := &in.Headers, &out.Headers
 		*out = make(map[string]string, len(*in))
@@ -318,6 +589,26 @@ func (in *VaultConnectionSpec) DeepCopyInto(out *VaultConnectionSpec) {
 			(*out)[key] = val
 		}
 	}
+	if in.HeadersFrom != nil {
+		in, out := &in.HeadersFrom, &out.HeadersFrom
+		*out = make([]SecretKeySelector, len(*in))
+		copy(*out, *in)
+	}
+	if in.NoProxy != nil {
+		in, out := &in.NoProxy, &out.NoProxy
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.MaxRetries != nil {
+		in, out := &in.MaxRetries, &out.MaxRetries
+		*out = new(int)
+		**out = **in
+	}
+	if in.RateLimit != nil {
+		in, out := &in.RateLimit, &out.RateLimit
+		*out = new(VaultConnectionRateLimit)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VaultConnectionSpec.
@@ -333,6 +624,20 @@ func (in *VaultConnectionSpec) DeepCopy() *VaultConnectionSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VaultConnectionStatus) DeepCopyInto(out *VaultConnectionStatus) {
 	*out = *in
+	if in.HeadersSecretVersions != nil {
+		in, out := &in.HeadersSecretVersions, &out.HeadersSecretVersions
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VaultConnectionStatus.
@@ -351,7 +656,7 @@ func (in *VaultDynamicSecret) DeepCopyInto(out *VaultDynamicSecret) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VaultDynamicSecret.
@@ -429,6 +734,13 @@ func (in *VaultDynamicSecretSpec) DeepCopy() *VaultDynamicSecretSpec {
 func (in *VaultDynamicSecretStatus) DeepCopyInto(out *VaultDynamicSecretStatus) {
 	*out = *in
 	out.SecretLease = in.SecretLease
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VaultDynamicSecretStatus.
@@ -447,7 +759,7 @@ func (in *VaultPKISecret) DeepCopyInto(out *VaultPKISecret) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VaultPKISecret.
@@ -539,6 +851,13 @@ func (in *VaultPKISecretSpec) DeepCopy() *VaultPKISecretSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VaultPKISecretStatus) DeepCopyInto(out *VaultPKISecretStatus) {
 	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VaultPKISecretStatus.
@@ -551,6 +870,169 @@ func (in *VaultPKISecretStatus) DeepCopy() *VaultPKISecretStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VaultSecretBundle) DeepCopyInto(out *VaultSecretBundle) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VaultSecretBundle.
+func (in *VaultSecretBundle) DeepCopy() *VaultSecretBundle {
+	if in == nil {
+		return nil
+	}
+	out := new(VaultSecretBundle)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *VaultSecretBundle) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VaultSecretBundleList) DeepCopyInto(out *VaultSecretBundleList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]VaultSecretBundle, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VaultSecretBundleList.
+func (in *VaultSecretBundleList) DeepCopy() *VaultSecretBundleList {
+	if in == nil {
+		return nil
+	}
+	out := new(VaultSecretBundleList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *VaultSecretBundleList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VaultSecretBundleSource) DeepCopyInto(out *VaultSecretBundleSource) {
+	*out = *in
+	if in.Params != nil {
+		in, out := &in.Params, &out.Params
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VaultSecretBundleSource.
+func (in *VaultSecretBundleSource) DeepCopy() *VaultSecretBundleSource {
+	if in == nil {
+		return nil
+	}
+	out := new(VaultSecretBundleSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VaultSecretBundleSourceStatus) DeepCopyInto(out *VaultSecretBundleSourceStatus) {
+	*out = *in
+	if in.Keys != nil {
+		in, out := &in.Keys, &out.Keys
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.SecretLease != nil {
+		in, out := &in.SecretLease, &out.SecretLease
+		*out = new(VaultSecretLease)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VaultSecretBundleSourceStatus.
+func (in *VaultSecretBundleSourceStatus) DeepCopy() *VaultSecretBundleSourceStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(VaultSecretBundleSourceStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VaultSecretBundleSpec) DeepCopyInto(out *VaultSecretBundleSpec) {
+	*out = *in
+	if in.Sources != nil {
+		in, out := &in.Sources, &out.Sources
+		*out = make([]VaultSecretBundleSource, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.RolloutRestartTargets != nil {
+		in, out := &in.RolloutRestartTargets, &out.RolloutRestartTargets
+		*out = make([]RolloutRestartTarget, len(*in))
+		copy(*out, *in)
+	}
+	in.Destination.DeepCopyInto(&out.Destination)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VaultSecretBundleSpec.
+func (in *VaultSecretBundleSpec) DeepCopy() *VaultSecretBundleSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(VaultSecretBundleSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VaultSecretBundleStatus) DeepCopyInto(out *VaultSecretBundleStatus) {
+	*out = *in
+	if in.Sources != nil {
+		in, out := &in.Sources, &out.Sources
+		*out = make([]VaultSecretBundleSourceStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VaultSecretBundleStatus.
+func (in *VaultSecretBundleStatus) DeepCopy() *VaultSecretBundleStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(VaultSecretBundleStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VaultSecretLease) DeepCopyInto(out *VaultSecretLease) {
 	*out = *in
@@ -566,13 +1048,33 @@ func (in *VaultSecretLease) DeepCopy() *VaultSecretLease {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VaultSecretVersion) DeepCopyInto(out *VaultSecretVersion) {
+	*out = *in
+	in.CreatedTime.DeepCopyInto(&out.CreatedTime)
+	if in.DeletionTime != nil {
+		in, out := &in.DeletionTime, &out.DeletionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VaultSecretVersion.
+func (in *VaultSecretVersion) DeepCopy() *VaultSecretVersion {
+	if in == nil {
+		return nil
+	}
+	out := new(VaultSecretVersion)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VaultStaticSecret) DeepCopyInto(out *VaultStaticSecret) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VaultStaticSecret.
@@ -625,9 +1127,29 @@ func (in *VaultStaticSecretList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VaultStaticSecretPrefix) DeepCopyInto(out *VaultStaticSecretPrefix) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VaultStaticSecretPrefix.
+func (in *VaultStaticSecretPrefix) DeepCopy() *VaultStaticSecretPrefix {
+	if in == nil {
+		return nil
+	}
+	out := new(VaultStaticSecretPrefix)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VaultStaticSecretSpec) DeepCopyInto(out *VaultStaticSecretSpec) {
 	*out = *in
+	if in.Prefix != nil {
+		in, out := &in.Prefix, &out.Prefix
+		*out = new(VaultStaticSecretPrefix)
+		**out = **in
+	}
 	if in.RolloutRestartTargets != nil {
 		in, out := &in.RolloutRestartTargets, &out.RolloutRestartTargets
 		*out = make([]RolloutRestartTarget, len(*in))
@@ -649,6 +1171,18 @@ func (in *VaultStaticSecretSpec) DeepCopy() *VaultStaticSecretSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VaultStaticSecretStatus) DeepCopyInto(out *VaultStaticSecretStatus) {
 	*out = *in
+	if in.SecretVersion != nil {
+		in, out := &in.SecretVersion, &out.SecretVersion
+		*out = new(VaultSecretVersion)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VaultStaticSecretStatus.
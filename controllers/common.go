@@ -7,14 +7,24 @@ import (
 	"context"
 	"fmt"
 	"math/rand"
+	"strings"
 	"time"
 
 	secretsv1alpha1 "github.com/hashicorp/vault-secrets-operator/api/v1alpha1"
 	"github.com/hashicorp/vault-secrets-operator/internal/common"
+	"github.com/hashicorp/vault-secrets-operator/internal/consts"
+	"github.com/hashicorp/vault-secrets-operator/internal/helpers"
+	"github.com/hashicorp/vault-secrets-operator/internal/vault"
 
 	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
 var random = rand.New(rand.NewSource(int64(time.Now().Nanosecond())))
@@ -128,3 +138,99 @@ func removeFinalizers(ctx context.Context, c client.Client, log logr.Logger, obj
 	}
 	log.Info(fmt.Sprintf("Removed %d finalizers", cnt))
 }
+
+// handleHeadersSecrets prunes all of o's referent Client(s) from the ClientFactory's cache
+// whenever one of its HeadersFrom Secrets has changed since it was last observed,
+// this ensures that all new Vault clients send the current headers.
+// The observed versions are updated to the Secrets' current resourceVersions.
+func handleHeadersSecrets(ctx context.Context, c client.Client, clientFactory vault.CachingClientFactory,
+	recorder record.EventRecorder, o client.Object, refs []secretsv1alpha1.SecretKeySelector,
+	observedVersions *map[string]string,
+) error {
+	versions := make(map[string]string)
+	var changed []string
+	for _, ref := range refs {
+		if _, ok := versions[ref.Name]; ok {
+			continue
+		}
+
+		s := &corev1.Secret{}
+		if err := c.Get(ctx, client.ObjectKey{
+			Namespace: o.GetNamespace(),
+			Name:      ref.Name,
+		}, s); err != nil {
+			return err
+		}
+
+		versions[ref.Name] = s.GetResourceVersion()
+		if v, ok := (*observedVersions)[ref.Name]; ok && v != s.GetResourceVersion() {
+			changed = append(changed, ref.Name)
+		}
+	}
+
+	if len(changed) > 0 {
+		count, err := clientFactory.Prune(ctx, c, o, vault.CachingClientFactoryPruneRequest{
+			FilterFunc:   filterAllCacheRefs,
+			PruneStorage: true,
+		})
+		if err != nil {
+			return err
+		}
+		log.FromContext(ctx).Info("Headers secret changed, pruned Client cache", "count", count, "secrets", changed)
+		recorder.Eventf(o, corev1.EventTypeNormal, consts.ReasonHeadersSecretRotated,
+			"Headers secret %s changed, rebuilding Vault clients", strings.Join(changed, ", "))
+	}
+
+	if len(versions) == 0 {
+		versions = nil
+	}
+	*observedVersions = versions
+
+	return nil
+}
+
+// referencesHeadersSecret returns true if any of refs selects a key of the named Secret.
+func referencesHeadersSecret(refs []secretsv1alpha1.SecretKeySelector, name string) bool {
+	for _, ref := range refs {
+		if ref.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// mapTemplatesConfigMap returns a handler.MapFunc that returns a reconcile.Request for every
+// object of list's type that references obj as its Destination's TemplatesConfigMap.
+func mapTemplatesConfigMap(c client.Client, list client.ObjectList) handler.MapFunc {
+	return func(obj client.Object) []reconcile.Request {
+		ctx := context.Background()
+		objs := list.DeepCopyObject().(client.ObjectList)
+		if err := c.List(ctx, objs, client.InNamespace(obj.GetNamespace())); err != nil {
+			log.FromContext(ctx).Error(err, "Failed to list resources", "configmap", client.ObjectKeyFromObject(obj))
+			return nil
+		}
+		items, err := meta.ExtractList(objs)
+		if err != nil {
+			log.FromContext(ctx).Error(err, "Failed to extract resources", "configmap", client.ObjectKeyFromObject(obj))
+			return nil
+		}
+
+		var requests []reconcile.Request
+		for _, item := range items {
+			o, ok := item.(client.Object)
+			if !ok {
+				continue
+			}
+			m, err := helpers.NewSyncableSecretMetaData(o)
+			if err != nil {
+				continue
+			}
+			if m.Destination.TemplatesConfigMap == obj.GetName() {
+				requests = append(requests, reconcile.Request{
+					NamespacedName: client.ObjectKeyFromObject(o),
+				})
+			}
+		}
+		return requests
+	}
+}
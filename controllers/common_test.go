@@ -0,0 +1,155 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	secretsv1alpha1 "github.com/hashicorp/vault-secrets-operator/api/v1alpha1"
+	"github.com/hashicorp/vault-secrets-operator/internal/vault"
+)
+
+type pruneCountingClientFactory struct {
+	vault.CachingClientFactory
+	pruned int
+}
+
+func (f *pruneCountingClientFactory) Prune(context.Context, client.Client, client.Object, vault.CachingClientFactoryPruneRequest) (int, error) {
+	f.pruned++
+	return 1, nil
+}
+
+func Test_handleHeadersSecrets(t *testing.T) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "gateway",
+			Namespace:       "tenant-1",
+			ResourceVersion: "2",
+		},
+	}
+	o := &secretsv1alpha1.VaultConnection{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "default",
+			Namespace: "tenant-1",
+		},
+		Spec: secretsv1alpha1.VaultConnectionSpec{
+			HeadersFrom: []secretsv1alpha1.SecretKeySelector{
+				{Name: "gateway", Key: "X-Api-Key"},
+				{Name: "gateway", Key: "X-Tenant"},
+			},
+		},
+	}
+
+	tests := map[string]struct {
+		observed       map[string]string
+		refs           []secretsv1alpha1.SecretKeySelector
+		expected       map[string]string
+		expectedPruned int
+	}{
+		"first observed": {
+			refs:     o.Spec.HeadersFrom,
+			expected: map[string]string{"gateway": "2"},
+		},
+		"unchanged": {
+			observed: map[string]string{"gateway": "2"},
+			refs:     o.Spec.HeadersFrom,
+			expected: map[string]string{"gateway": "2"},
+		},
+		"changed": {
+			observed:       map[string]string{"gateway": "1"},
+			refs:           o.Spec.HeadersFrom,
+			expected:       map[string]string{"gateway": "2"},
+			expectedPruned: 1,
+		},
+		"removed": {
+			observed: map[string]string{"gateway": "1"},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			factory := &pruneCountingClientFactory{}
+			recorder := record.NewFakeRecorder(1)
+			c := fake.NewClientBuilder().WithObjects(secret).Build()
+
+			observed := tc.observed
+			require.NoError(t, handleHeadersSecrets(context.Background(), c, factory, recorder, o, tc.refs, &observed))
+			assert.Equal(t, tc.expected, observed)
+			assert.Equal(t, tc.expectedPruned, factory.pruned)
+			assert.Len(t, recorder.Events, tc.expectedPruned)
+		})
+	}
+}
+
+func Test_mapTemplatesConfigMap(t *testing.T) {
+	s := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(s))
+	require.NoError(t, secretsv1alpha1.AddToScheme(s))
+
+	newVSS := func(namespace, name, configMap string) *secretsv1alpha1.VaultStaticSecret {
+		return &secretsv1alpha1.VaultStaticSecret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: namespace,
+			},
+			Spec: secretsv1alpha1.VaultStaticSecretSpec{
+				Destination: secretsv1alpha1.Destination{
+					Name:               name,
+					TemplatesConfigMap: configMap,
+				},
+			},
+		}
+	}
+	c := fake.NewClientBuilder().WithScheme(s).WithObjects(
+		newVSS("tenant-1", "app", "templates"),
+		newVSS("tenant-1", "app-2", "templates"),
+		newVSS("tenant-1", "other", "other-templates"),
+		newVSS("tenant-1", "none", ""),
+		newVSS("tenant-2", "app", "templates"),
+	).Build()
+
+	tests := map[string]struct {
+		configMap client.Object
+		expected  []reconcile.Request
+	}{
+		"referenced": {
+			configMap: &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "templates", Namespace: "tenant-1"},
+			},
+			expected: []reconcile.Request{
+				{NamespacedName: client.ObjectKey{Namespace: "tenant-1", Name: "app"}},
+				{NamespacedName: client.ObjectKey{Namespace: "tenant-1", Name: "app-2"}},
+			},
+		},
+		"unreferenced": {
+			configMap: &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "unused", Namespace: "tenant-1"},
+			},
+		},
+		"other-namespace": {
+			configMap: &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "other-templates", Namespace: "tenant-2"},
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			mapFunc := mapTemplatesConfigMap(c, &secretsv1alpha1.VaultStaticSecretList{})
+			assert.ElementsMatch(t, tc.expected, mapFunc(tc.configMap))
+		})
+	}
+}
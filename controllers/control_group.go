@@ -0,0 +1,64 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/hashicorp/vault-secrets-operator/internal/consts"
+	"github.com/hashicorp/vault-secrets-operator/internal/vault"
+)
+
+// controlGroupPollInterval is the interval at which a pending control group request
+// is checked for authorization.
+const controlGroupPollInterval = 30 * time.Second
+
+// resumeControlGroupRequest returns the Client that obj should be synced with. If obj has a pending
+// control group request, identified by accessor, that has since been authorized, then the returned
+// Client responds to the original request with the authorized response. accessor is cleared once
+// the request is no longer pending, or if it is unknown, in which case a new request will be made.
+// A vault.ControlGroupError is returned while the request is awaiting authorization.
+func resumeControlGroupRequest(ctx context.Context, c vault.Client, obj client.Object, accessor *string) (vault.Client, error) {
+	if *accessor == "" {
+		return c, nil
+	}
+
+	resp, path, err := c.ReadControlGroup(ctx, obj.GetUID(), *accessor)
+	if errors.Is(err, vault.ErrControlGroupRequestNotFound) {
+		// the wrapping token is no longer available, so a new request must be made.
+		*accessor = ""
+		return c, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	*accessor = ""
+	return vault.WithControlGroupResponse(c, path, resp), nil
+}
+
+// handleControlGroupError records the control group request of err as obj's pending request
+// in accessor, an event is recorded for every new request. Returns false if err is not
+// a vault.ControlGroupError.
+func handleControlGroupError(recorder record.EventRecorder, obj client.Object, accessor *string, err error) bool {
+	var cgErr *vault.ControlGroupError
+	if !errors.As(err, &cgErr) {
+		return false
+	}
+
+	vault.TrackControlGroupRequest(obj.GetUID(), cgErr)
+	if *accessor != cgErr.Accessor {
+		recorder.Eventf(obj, corev1.EventTypeNormal, consts.ReasonControlGroupPending,
+			"Awaiting control group authorization, path=%s, accessor=%s", cgErr.Path, cgErr.Accessor)
+	}
+	*accessor = cgErr.Accessor
+
+	return true
+}
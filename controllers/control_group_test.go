@@ -0,0 +1,133 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/hashicorp/vault/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+
+	secretsv1alpha1 "github.com/hashicorp/vault-secrets-operator/api/v1alpha1"
+	"github.com/hashicorp/vault-secrets-operator/internal/vault"
+)
+
+// controlGroupClient is a vault.Client that responds to ReadControlGroup with resp and err,
+// all reads are answered with an empty secret.
+type controlGroupClient struct {
+	vault.Client
+	resp *api.Secret
+	path string
+	err  error
+}
+
+func (c *controlGroupClient) ReadControlGroup(_ context.Context, _ types.UID, _ string) (*api.Secret, string, error) {
+	return c.resp, c.path, c.err
+}
+
+func (c *controlGroupClient) Read(_ context.Context, _ string) (*api.Secret, error) {
+	return &api.Secret{}, nil
+}
+
+func Test_resumeControlGroupRequest(t *testing.T) {
+	resp := &api.Secret{LeaseID: "lease-1"}
+
+	tests := map[string]struct {
+		accessor     string
+		client       *controlGroupClient
+		wantAccessor string
+		wantResp     bool
+		wantErr      assert.ErrorAssertionFunc
+	}{
+		"no-pending-request": {
+			client:  &controlGroupClient{err: errors.New("unexpected")},
+			wantErr: assert.NoError,
+		},
+		"request-not-found": {
+			accessor: "accessor-1",
+			client:   &controlGroupClient{err: vault.ErrControlGroupRequestNotFound},
+			wantErr:  assert.NoError,
+		},
+		"request-pending": {
+			accessor: "accessor-1",
+			client: &controlGroupClient{err: &vault.ControlGroupError{
+				Accessor: "accessor-1",
+				Path:     "kv/data/app",
+			}},
+			wantAccessor: "accessor-1",
+			wantErr: func(t assert.TestingT, err error, i ...interface{}) bool {
+				var cgErr *vault.ControlGroupError
+				return assert.ErrorAs(t, err, &cgErr, i...)
+			},
+		},
+		"request-authorized": {
+			accessor: "accessor-1",
+			client: &controlGroupClient{
+				resp: resp,
+				path: "kv/data/app",
+			},
+			wantResp: true,
+			wantErr:  assert.NoError,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			o := &secretsv1alpha1.VaultStaticSecret{
+				ObjectMeta: metav1.ObjectMeta{UID: "uid-1"},
+			}
+			accessor := tt.accessor
+
+			c, err := resumeControlGroupRequest(ctx, tt.client, o, &accessor)
+			if !tt.wantErr(t, err, "resumeControlGroupRequest()") {
+				return
+			}
+			assert.Equal(t, tt.wantAccessor, accessor)
+			if err != nil {
+				return
+			}
+
+			got, err := c.Read(ctx, "kv/data/app")
+			require.NoError(t, err)
+			if tt.wantResp {
+				assert.Same(t, resp, got)
+			} else {
+				assert.Same(t, tt.client, c)
+				assert.NotSame(t, resp, got)
+			}
+		})
+	}
+}
+
+func Test_handleControlGroupError(t *testing.T) {
+	recorder := record.NewFakeRecorder(10)
+	o := &secretsv1alpha1.VaultStaticSecret{
+		ObjectMeta: metav1.ObjectMeta{UID: "uid-1"},
+	}
+	var accessor string
+
+	assert.False(t, handleControlGroupError(recorder, o, &accessor, errors.New("other")))
+	assert.Empty(t, accessor)
+	assert.Len(t, recorder.Events, 0)
+
+	cgErr := &vault.ControlGroupError{
+		Accessor: "accessor-1",
+		Path:     "kv/data/app",
+	}
+	assert.True(t, handleControlGroupError(recorder, o, &accessor, cgErr))
+	assert.Equal(t, "accessor-1", accessor)
+	require.Len(t, recorder.Events, 1)
+	assert.Equal(t, "Normal ControlGroupPending Awaiting control group authorization, "+
+		"path=kv/data/app, accessor=accessor-1", <-recorder.Events)
+
+	// an event is only recorded for a new request.
+	assert.True(t, handleControlGroupError(recorder, o, &accessor, cgErr))
+	assert.Len(t, recorder.Events, 0)
+}
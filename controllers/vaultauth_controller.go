@@ -11,13 +11,20 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	secretsv1alpha1 "github.com/hashicorp/vault-secrets-operator/api/v1alpha1"
 	"github.com/hashicorp/vault-secrets-operator/internal/common"
@@ -28,6 +35,10 @@ import (
 
 const vaultAuthFinalizer = "vaultauth.secrets.hashicorp.com/finalizer"
 
+// wrappingTokenCheckInterval is the interval at which a VaultAuth with response-wrapped
+// credentials is checked for a consumed wrapping token.
+const wrappingTokenCheckInterval = 30 * time.Second
+
 // VaultAuthReconciler reconciles a VaultAuth object
 type VaultAuthReconciler struct {
 	client.Client
@@ -89,10 +100,12 @@ func (r *VaultAuthReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 		errs = errors.Join(errs, err)
 	}
 
-	if _, err = common.GetVaultConnectionWithRetry(ctx, r.Client, connName, time.Millisecond*500, 60); err != nil {
+	connObj, err := common.GetVaultConnectionWithRetry(ctx, r.Client, connName, time.Millisecond*500, 60)
+	if err != nil {
 		errs = errors.Join(errs, err)
 		logger.Error(err, "Failed to find VaultConnectionRef")
 	}
+	setDegradedCondition(o, connObj)
 
 	// prune old referent Client from the ClientFactory's cache for all older generations of self.
 	// this is a bit of a sledgehammer, not all updated attributes of VaultConnection
@@ -107,6 +120,18 @@ func (r *VaultAuthReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 		errs = errors.Join(errs, err)
 	}
 
+	if err := handleHeadersSecrets(ctx, r.Client, r.ClientFactory, r.Recorder, o,
+		o.Spec.HeadersFrom, &o.Status.HeadersSecretVersions); err != nil {
+		logger.Error(err, "Failed to handle the headers secrets")
+		errs = errors.Join(errs, err)
+	}
+
+	requeueAfter, err := r.handleWrappingTokenError(o)
+	if err != nil {
+		logger.Error(err, "Failed to login to Vault")
+		errs = errors.Join(errs, err)
+	}
+
 	if errs == nil {
 		o.Status.Valid = true
 	} else {
@@ -118,7 +143,24 @@ func (r *VaultAuthReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 	}
 
 	r.recordEvent(o, consts.ReasonAccepted, "Successfully handled VaultAuth resource request")
-	return ctrl.Result{}, nil
+	return ctrl.Result{RequeueAfter: requeueAfter}, nil
+}
+
+// handleWrappingTokenError returns the error of the last login with o, if its response-wrapping
+// token has already been consumed, an event is recorded for the error. The login is made by
+// o's consumers, so a VaultAuth with response-wrapped credentials is requeued after the
+// returned interval, in order to report any future error, or to clear a resolved one.
+func (r *VaultAuthReconciler) handleWrappingTokenError(o *secretsv1alpha1.VaultAuth) (time.Duration, error) {
+	if !vault.HasWrappedCredentials(o) {
+		return 0, nil
+	}
+
+	err := vault.GetWrappingTokenError(o)
+	if err != nil {
+		r.Recorder.Eventf(o, corev1.EventTypeWarning, consts.ReasonVaultClientError,
+			"Failed to login to Vault: %s", err)
+	}
+	return wrappingTokenCheckInterval, err
 }
 
 func (r *VaultAuthReconciler) recordEvent(a *secretsv1alpha1.VaultAuth, reason, msg string, i ...interface{}) {
@@ -164,15 +206,107 @@ func (r *VaultAuthReconciler) handleFinalizer(ctx context.Context, o *secretsv1a
 		if err := r.Update(ctx, o); err != nil {
 			return ctrl.Result{}, err
 		}
+		vault.DeleteWrappingTokenErrors(o)
 	}
 
 	return ctrl.Result{}, nil
 }
 
+// mapVaultConnection returns a reconcile.Request for every VaultAuth that
+// references obj as its VaultConnection.
+func (r *VaultAuthReconciler) mapVaultConnection(obj client.Object) []reconcile.Request {
+	ctx := context.Background()
+	var requests []reconcile.Request
+	auths := &secretsv1alpha1.VaultAuthList{}
+	if err := r.Client.List(ctx, auths); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to list VaultAuths", "connection", client.ObjectKeyFromObject(obj))
+		return nil
+	}
+	for _, o := range auths.Items {
+		connName, err := common.GetConnectionNamespacedName(&o)
+		if err != nil {
+			continue
+		}
+		if connName == client.ObjectKeyFromObject(obj) {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: client.ObjectKeyFromObject(&o),
+			})
+		}
+	}
+	return requests
+}
+
+// mapSecret returns a reconcile.Request for every VaultAuth that
+// references obj as one of its headers Secrets.
+func (r *VaultAuthReconciler) mapSecret(obj client.Object) []reconcile.Request {
+	ctx := context.Background()
+	var requests []reconcile.Request
+	auths := &secretsv1alpha1.VaultAuthList{}
+	if err := r.Client.List(ctx, auths, client.InNamespace(obj.GetNamespace())); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to list VaultAuths", "secret", client.ObjectKeyFromObject(obj))
+		return nil
+	}
+	for _, o := range auths.Items {
+		if referencesHeadersSecret(o.Spec.HeadersFrom, obj.GetName()) {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: client.ObjectKeyFromObject(&o),
+			})
+		}
+	}
+	return requests
+}
+
+// setDegradedCondition marks the VaultAuth as Degraded whenever its VaultConnection
+// is missing or unhealthy.
+func setDegradedCondition(o *secretsv1alpha1.VaultAuth, connObj *secretsv1alpha1.VaultConnection) {
+	c := metav1.Condition{
+		Type:               consts.TypeDegraded,
+		Status:             metav1.ConditionFalse,
+		Reason:             consts.ReasonConnectionHealthy,
+		ObservedGeneration: o.Generation,
+	}
+	if connObj == nil {
+		c.Status = metav1.ConditionTrue
+		c.Reason = consts.ReasonConnectionUnhealthy
+		c.Message = "VaultConnection was not found"
+	} else if h := meta.FindStatusCondition(connObj.Status.Conditions, consts.TypeHealthy); h != nil {
+		c.Message = fmt.Sprintf("VaultConnection %s: %s", connObj.Name, h.Message)
+		if h.Status != metav1.ConditionTrue {
+			c.Status = metav1.ConditionTrue
+			c.Reason = consts.ReasonConnectionUnhealthy
+		}
+	}
+
+	meta.SetStatusCondition(&o.Status.Conditions, c)
+}
+
+// vaultConnectionHealthChanged filters VaultConnection events, so that only the
+// changes of its Healthy condition trigger a reconciliation.
+var vaultConnectionHealthChanged = predicate.Funcs{
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		oldObj, ok := e.ObjectOld.(*secretsv1alpha1.VaultConnection)
+		if !ok {
+			return false
+		}
+		newObj, ok := e.ObjectNew.(*secretsv1alpha1.VaultConnection)
+		if !ok {
+			return false
+		}
+		return meta.IsStatusConditionTrue(oldObj.Status.Conditions, consts.TypeHealthy) !=
+			meta.IsStatusConditionTrue(newObj.Status.Conditions, consts.TypeHealthy)
+	},
+}
+
 // SetupWithManager sets up the controller with the Manager.
 func (r *VaultAuthReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&secretsv1alpha1.VaultAuth{}).
-		WithEventFilter(predicate.GenerationChangedPredicate{}).
+		For(&secretsv1alpha1.VaultAuth{},
+			builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Watches(&source.Kind{Type: &secretsv1alpha1.VaultConnection{}},
+			handler.EnqueueRequestsFromMapFunc(r.mapVaultConnection),
+			builder.WithPredicates(vaultConnectionHealthChanged)).
+		Watches(&source.Kind{Type: &corev1.Secret{}},
+			handler.EnqueueRequestsFromMapFunc(r.mapSecret),
+			builder.WithPredicates(predicate.ResourceVersionChangedPredicate{})).
 		Complete(r)
 }
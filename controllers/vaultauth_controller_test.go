@@ -0,0 +1,126 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package controllers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	secretsv1alpha1 "github.com/hashicorp/vault-secrets-operator/api/v1alpha1"
+	"github.com/hashicorp/vault-secrets-operator/internal/consts"
+	"github.com/hashicorp/vault-secrets-operator/internal/vault/credentials"
+)
+
+func Test_setDegradedCondition(t *testing.T) {
+	tests := map[string]struct {
+		connObj  *secretsv1alpha1.VaultConnection
+		expected metav1.ConditionStatus
+		reason   string
+	}{
+		"connection not found": {
+			connObj:  nil,
+			expected: metav1.ConditionTrue,
+			reason:   consts.ReasonConnectionUnhealthy,
+		},
+		"connection healthy": {
+			connObj: &secretsv1alpha1.VaultConnection{
+				Status: secretsv1alpha1.VaultConnectionStatus{
+					Conditions: []metav1.Condition{
+						{
+							Type:   consts.TypeHealthy,
+							Status: metav1.ConditionTrue,
+							Reason: consts.ReasonVaultHealthy,
+						},
+					},
+				},
+			},
+			expected: metav1.ConditionFalse,
+			reason:   consts.ReasonConnectionHealthy,
+		},
+		"connection unhealthy": {
+			connObj: &secretsv1alpha1.VaultConnection{
+				Status: secretsv1alpha1.VaultConnectionStatus{
+					Conditions: []metav1.Condition{
+						{
+							Type:   consts.TypeHealthy,
+							Status: metav1.ConditionFalse,
+							Reason: consts.ReasonVaultSealed,
+						},
+					},
+				},
+			},
+			expected: metav1.ConditionTrue,
+			reason:   consts.ReasonConnectionUnhealthy,
+		},
+		"connection not yet checked": {
+			connObj:  &secretsv1alpha1.VaultConnection{},
+			expected: metav1.ConditionFalse,
+			reason:   consts.ReasonConnectionHealthy,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			o := &secretsv1alpha1.VaultAuth{}
+			setDegradedCondition(o, tc.connObj)
+
+			c := meta.FindStatusCondition(o.Status.Conditions, consts.TypeDegraded)
+			require.NotNil(t, c)
+			assert.Equal(t, tc.expected, c.Status)
+			assert.Equal(t, tc.reason, c.Reason)
+		})
+	}
+}
+
+func TestVaultAuthReconciler_handleWrappingTokenError(t *testing.T) {
+	tests := map[string]struct {
+		spec         secretsv1alpha1.VaultAuthSpec
+		requeueAfter time.Duration
+	}{
+		"not-wrapped": {
+			spec: secretsv1alpha1.VaultAuthSpec{
+				Method: credentials.ProviderMethodAppRole,
+				AppRole: &secretsv1alpha1.VaultAuthConfigAppRole{
+					RoleID: "role-1",
+				},
+			},
+		},
+		"wrapped": {
+			spec: secretsv1alpha1.VaultAuthSpec{
+				Method: credentials.ProviderMethodAppRole,
+				AppRole: &secretsv1alpha1.VaultAuthConfigAppRole{
+					RoleID:  "role-1",
+					Wrapped: true,
+				},
+			},
+			requeueAfter: wrappingTokenCheckInterval,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			recorder := record.NewFakeRecorder(1)
+			r := &VaultAuthReconciler{Recorder: recorder}
+			o := &secretsv1alpha1.VaultAuth{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "default",
+					Namespace: "tenant-1",
+					UID:       "uid-1",
+				},
+				Spec: tc.spec,
+			}
+
+			requeueAfter, err := r.handleWrappingTokenError(o)
+			require.NoError(t, err)
+			assert.Equal(t, tc.requeueAfter, requeueAfter)
+			assert.Len(t, recorder.Events, 0)
+		})
+	}
+}
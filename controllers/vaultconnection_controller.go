@@ -6,25 +6,38 @@ package controllers
 import (
 	"context"
 	"errors"
+	"fmt"
+	"time"
 
 	"github.com/hashicorp/vault-secrets-operator/internal/metrics"
+	"github.com/hashicorp/vault/api"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	secretsv1alpha1 "github.com/hashicorp/vault-secrets-operator/api/v1alpha1"
 	"github.com/hashicorp/vault-secrets-operator/internal/consts"
 	"github.com/hashicorp/vault-secrets-operator/internal/vault"
 )
 
-const vaultConnectionFinalizer = "vaultconnection.secrets.hashicorp.com/finalizer"
+const (
+	vaultConnectionFinalizer   = "vaultconnection.secrets.hashicorp.com/finalizer"
+	defaultHealthCheckInterval = time.Minute
+)
 
 // VaultConnectionReconciler reconciles a VaultConnection object
 type VaultConnectionReconciler struct {
@@ -39,6 +52,7 @@ type VaultConnectionReconciler struct {
 //+kubebuilder:rbac:groups=secrets.hashicorp.com,resources=vaultconnections/finalizers,verbs=update
 //+kubebuilder:rbac:groups="",resources=events,verbs=create;patch
 //+kubebuilder:rbac:groups="",resources=secrets,verbs=get
+//+kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch
 // needed for managing cached Clients, duplicated in vaultauth_controller.go
 //+kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create;delete;update;patch;deletecollection
 
@@ -68,34 +82,110 @@ func (r *VaultConnectionReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 		return r.handleFinalizer(ctx, o)
 	}
 
+	// the generation that was observed by the last successful health check.
+	var observedGeneration int64
+	if c := meta.FindStatusCondition(o.Status.Conditions, consts.TypeHealthy); c != nil {
+		observedGeneration = c.ObservedGeneration
+	}
+
+	wasValid := o.Status.Valid
+	lastStatus := o.Status.DeepCopy()
 	// assume that status is always invalid
 	o.Status.Valid = false
 
-	vaultConfig := &vault.ClientConfig{
-		CACertSecretRef: o.Spec.CACertSecretRef,
-		K8sNamespace:    o.ObjectMeta.Namespace,
-		Address:         o.Spec.Address,
-		SkipTLSVerify:   o.Spec.SkipTLSVerify,
-		TLSServerName:   o.Spec.TLSServerName,
-	}
-
 	var errs error
-	vaultClient, err := vault.MakeVaultClient(ctx, vaultConfig, r.Client)
-	if err != nil {
-		logger.Error(err, "Failed to construct Vault client")
-		r.Recorder.Eventf(o, corev1.EventTypeWarning, consts.ReasonVaultClientError, "Failed to construct Vault client: %s", err)
-
+	addresses := vault.ConnectionAddresses(o.Spec)
+	if len(addresses) == 0 {
+		err := errors.New("either address or addresses must be set")
+		logger.Error(err, "Invalid VaultConnection")
+		r.Recorder.Eventf(o, corev1.EventTypeWarning, consts.ReasonInvalidConfiguration, "Invalid VaultConnection: %s", err)
 		errs = errors.Join(errs, err)
 	}
 
-	if vaultClient != nil {
-		if _, err := vaultClient.Sys().SealStatusWithContext(ctx); err != nil {
-			logger.Error(err, "Failed to check Vault seal status, requeuing")
-			r.Recorder.Eventf(o, corev1.EventTypeWarning, "VaultClientError", "Failed to check Vault seal status: %s", err)
+	healthCheckInterval := defaultHealthCheckInterval
+	if o.Spec.HealthCheckInterval != "" {
+		d, err := time.ParseDuration(o.Spec.HealthCheckInterval)
+		if err != nil {
+			err = fmt.Errorf("invalid healthCheckInterval %q: %w", o.Spec.HealthCheckInterval, err)
+			logger.Error(err, "Invalid VaultConnection")
+			r.Recorder.Eventf(o, corev1.EventTypeWarning, consts.ReasonInvalidConfiguration, "Invalid VaultConnection: %s", err)
 			errs = errors.Join(errs, err)
 		} else {
-			o.Status.Valid = true
+			healthCheckInterval = d
+		}
+	}
+
+	vaultConfig := &vault.ClientConfig{
+		CACertSecretRef:    o.Spec.CACertSecretRef,
+		CACertConfigMapRef: o.Spec.CACertConfigMapRef,
+		CACertConfigMapKey: o.Spec.CACertConfigMapKey,
+		K8sNamespace:       o.ObjectMeta.Namespace,
+		// the CA configmap is owned by the VaultConnection.
+		CACertConfigMapNamespace: o.ObjectMeta.Namespace,
+		SkipTLSVerify:            o.Spec.SkipTLSVerify,
+		TLSServerName:            o.Spec.TLSServerName,
+		ClientCertSecretRef:      o.Spec.ClientCertSecretRef,
+	}
+	if err := vaultConfig.SetConnectionOptions(o); err != nil {
+		logger.Error(err, "Invalid VaultConnection")
+		r.Recorder.Eventf(o, corev1.EventTypeWarning, consts.ReasonInvalidConfiguration, "Invalid VaultConnection: %s", err)
+		errs = errors.Join(errs, err)
+		// no Vault client can be constructed from an invalid configuration.
+		addresses = nil
+	}
+
+	// check every address, the active address is the most preferred healthy one.
+	o.Status.ActiveAddress = ""
+	var addrErrs error
+	var active, reachable *vaultHealth
+	for _, addr := range addresses {
+		vaultConfig.Address = addr
+		vaultClient, err := vault.MakeVaultClient(ctx, vaultConfig, r.Client)
+		if err != nil {
+			logger.Error(err, "Failed to construct Vault client")
+			r.Recorder.Eventf(o, corev1.EventTypeWarning, consts.ReasonVaultClientError, "Failed to construct Vault client: %s", err)
+
+			// the client configuration is the same for all addresses.
+			addrErrs = errors.Join(addrErrs, err)
+			break
+		}
+
+		start := time.Now()
+		health, err := vaultClient.Sys().HealthWithContext(ctx)
+		if err != nil {
+			logger.Error(err, "Failed to check Vault health", "address", addr)
+			r.Recorder.Eventf(o, corev1.EventTypeWarning, consts.ReasonVaultClientError,
+				"Failed to check Vault health of %s: %s", addr, err)
+			addrErrs = errors.Join(addrErrs, err)
+			continue
 		}
+
+		metrics.SetVaultConnectionHealthCheckTime(o, addr, time.Since(start))
+		h := &vaultHealth{
+			address:  addr,
+			response: health,
+			// performance standbys serve reads when a read consistency is set.
+			perfStandbyOK: o.Spec.ReadConsistency != "",
+		}
+		if reachable == nil {
+			reachable = h
+		}
+
+		o.Status.Valid = true
+		if active == nil && h.healthy() {
+			active = h
+			o.Status.ActiveAddress = addr
+		}
+	}
+	// unreachable addresses are only an error when no address is reachable.
+	if !o.Status.Valid {
+		errs = errors.Join(errs, addrErrs)
+	}
+
+	if active != nil {
+		setHealthConditions(o, active)
+	} else {
+		setHealthConditions(o, reachable)
 	}
 
 	// prune old referent Client from the ClientFactory's cache for all older generations of self.
@@ -112,7 +202,28 @@ func (r *VaultConnectionReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 		errs = errors.Join(errs, err)
 	}
 
-	if err := r.updateStatus(ctx, o); err != nil {
+	if err := r.handleClientCertSecret(ctx, o); err != nil {
+		logger.Error(err, "Failed to handle the client certificate secret")
+		errs = errors.Join(errs, err)
+	}
+
+	if err := r.handleProxySecret(ctx, o); err != nil {
+		logger.Error(err, "Failed to handle the proxy secret")
+		errs = errors.Join(errs, err)
+	}
+
+	if err := r.handleCACertConfigMap(ctx, o); err != nil {
+		logger.Error(err, "Failed to handle the CA certificate configmap")
+		errs = errors.Join(errs, err)
+	}
+
+	if err := handleHeadersSecrets(ctx, r.Client, r.ClientFactory, r.Recorder, o,
+		o.Spec.HeadersFrom, &o.Status.HeadersSecretVersions); err != nil {
+		logger.Error(err, "Failed to handle the headers secrets")
+		errs = errors.Join(errs, err)
+	}
+
+	if err := r.updateStatus(ctx, o, lastStatus); err != nil {
 		errs = errors.Join(errs, err)
 	}
 
@@ -120,8 +231,12 @@ func (r *VaultConnectionReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 		return ctrl.Result{}, errs
 	}
 
-	r.Recorder.Event(o, corev1.EventTypeNormal, consts.ReasonAccepted, "VaultConnection accepted")
-	return ctrl.Result{}, nil
+	// periodic health checks of an unchanged VaultConnection are not announced.
+	if !wasValid || o.Generation != observedGeneration {
+		r.Recorder.Event(o, corev1.EventTypeNormal, consts.ReasonAccepted, "VaultConnection accepted")
+	}
+
+	return ctrl.Result{RequeueAfter: healthCheckInterval}, nil
 }
 
 func (r *VaultConnectionReconciler) addFinalizer(ctx context.Context, o *secretsv1alpha1.VaultConnection) error {
@@ -135,9 +250,17 @@ func (r *VaultConnectionReconciler) addFinalizer(ctx context.Context, o *secrets
 	return nil
 }
 
-func (r *VaultConnectionReconciler) updateStatus(ctx context.Context, o *secretsv1alpha1.VaultConnection) error {
+// updateStatus updates o's status, unless it is unchanged from lastStatus, so that the periodic
+// health checks of an unchanged VaultConnection do not result in a status update.
+func (r *VaultConnectionReconciler) updateStatus(ctx context.Context, o *secretsv1alpha1.VaultConnection,
+	lastStatus *secretsv1alpha1.VaultConnectionStatus,
+) error {
 	logger := log.FromContext(ctx)
 	metrics.SetResourceStatus("vaultconnection", o, o.Status.Valid)
+	metrics.SetVaultConnectionHealth(o, meta.IsStatusConditionTrue(o.Status.Conditions, consts.TypeHealthy))
+	if equality.Semantic.DeepEqual(lastStatus, &o.Status) {
+		return nil
+	}
 	if err := r.Status().Update(ctx, o); err != nil {
 		logger.Error(err, "Failed to update the resource's status")
 		return err
@@ -145,6 +268,106 @@ func (r *VaultConnectionReconciler) updateStatus(ctx context.Context, o *secrets
 	return nil
 }
 
+// handleClientCertSecret prunes all referent Client(s) from the ClientFactory's cache
+// whenever the client certificate Secret has changed since it was last observed,
+// this ensures that all new Vault clients present the current client certificate.
+func (r *VaultConnectionReconciler) handleClientCertSecret(ctx context.Context, o *secretsv1alpha1.VaultConnection) error {
+	return r.handleObjectRef(ctx, o, &corev1.Secret{}, o.Spec.ClientCertSecretRef,
+		&o.Status.ClientCertSecretVersion, consts.ReasonClientCertRotated, "Client certificate secret")
+}
+
+// handleProxySecret prunes all referent Client(s) from the ClientFactory's cache
+// whenever the proxy credentials Secret has changed since it was last observed,
+// this ensures that all new Vault clients authenticate to the proxy with the current credentials.
+func (r *VaultConnectionReconciler) handleProxySecret(ctx context.Context, o *secretsv1alpha1.VaultConnection) error {
+	return r.handleObjectRef(ctx, o, &corev1.Secret{}, o.Spec.ProxySecretRef,
+		&o.Status.ProxySecretVersion, consts.ReasonProxySecretRotated, "Proxy secret")
+}
+
+// handleCACertConfigMap prunes all referent Client(s) from the ClientFactory's cache
+// whenever the CA certificate ConfigMap has changed since it was last observed,
+// this ensures that all new Vault clients trust the current CA certificates.
+func (r *VaultConnectionReconciler) handleCACertConfigMap(ctx context.Context, o *secretsv1alpha1.VaultConnection) error {
+	return r.handleObjectRef(ctx, o, &corev1.ConfigMap{}, o.Spec.CACertConfigMapRef,
+		&o.Status.CACertConfigMapVersion, consts.ReasonCACertRotated, "CA certificate configmap")
+}
+
+// handleObjectRef prunes all referent Client(s) from the ClientFactory's cache
+// whenever the named object's resourceVersion differs from the observed version,
+// the observed version is updated to the object's current resourceVersion.
+func (r *VaultConnectionReconciler) handleObjectRef(ctx context.Context, o *secretsv1alpha1.VaultConnection,
+	obj client.Object, name string, observedVersion *string, reason, kind string,
+) error {
+	if name == "" {
+		*observedVersion = ""
+		return nil
+	}
+
+	if err := r.Client.Get(ctx, client.ObjectKey{
+		Namespace: o.Namespace,
+		Name:      name,
+	}, obj); err != nil {
+		return err
+	}
+
+	if *observedVersion != "" && *observedVersion != obj.GetResourceVersion() {
+		count, err := r.ClientFactory.Prune(ctx, r.Client, o, vault.CachingClientFactoryPruneRequest{
+			FilterFunc:   filterAllCacheRefs,
+			PruneStorage: true,
+		})
+		if err != nil {
+			return err
+		}
+		log.FromContext(ctx).Info(kind+" changed, pruned Client cache", "count", count)
+		r.Recorder.Eventf(o, corev1.EventTypeNormal, reason,
+			"%s %s changed, rebuilding Vault clients", kind, name)
+	}
+	*observedVersion = obj.GetResourceVersion()
+
+	return nil
+}
+
+// mapSecret returns a reconcile.Request for every VaultConnection that
+// references obj as its client certificate, proxy, or headers Secret.
+func (r *VaultConnectionReconciler) mapSecret(obj client.Object) []reconcile.Request {
+	ctx := context.Background()
+	var requests []reconcile.Request
+	conns := &secretsv1alpha1.VaultConnectionList{}
+	if err := r.Client.List(ctx, conns, client.InNamespace(obj.GetNamespace())); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to list VaultConnections", "secret", client.ObjectKeyFromObject(obj))
+		return nil
+	}
+	for _, o := range conns.Items {
+		if o.Spec.ClientCertSecretRef == obj.GetName() || o.Spec.ProxySecretRef == obj.GetName() ||
+			referencesHeadersSecret(o.Spec.HeadersFrom, obj.GetName()) {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: client.ObjectKeyFromObject(&o),
+			})
+		}
+	}
+	return requests
+}
+
+// mapConfigMap returns a reconcile.Request for every VaultConnection that
+// references obj as its CA certificate ConfigMap.
+func (r *VaultConnectionReconciler) mapConfigMap(obj client.Object) []reconcile.Request {
+	ctx := context.Background()
+	var requests []reconcile.Request
+	conns := &secretsv1alpha1.VaultConnectionList{}
+	if err := r.Client.List(ctx, conns, client.InNamespace(obj.GetNamespace())); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to list VaultConnections", "configmap", client.ObjectKeyFromObject(obj))
+		return nil
+	}
+	for _, o := range conns.Items {
+		if o.Spec.CACertConfigMapRef == obj.GetName() {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: client.ObjectKeyFromObject(&o),
+			})
+		}
+	}
+	return requests
+}
+
 func (r *VaultConnectionReconciler) handleFinalizer(ctx context.Context, o *secretsv1alpha1.VaultConnection) (ctrl.Result, error) {
 	if controllerutil.ContainsFinalizer(o, vaultConnectionFinalizer) {
 		if _, err := r.ClientFactory.Prune(ctx, r.Client, o, vault.CachingClientFactoryPruneRequest{
@@ -158,6 +381,9 @@ func (r *VaultConnectionReconciler) handleFinalizer(ctx context.Context, o *secr
 		if err := r.Update(ctx, o); err != nil {
 			return ctrl.Result{}, err
 		}
+		metrics.DeleteVaultConnectionHealth(o)
+		metrics.DeleteVaultConnectionHealthCheckTime(o)
+		vault.DeleteConnectionLimiter(o)
 	}
 
 	return ctrl.Result{}, nil
@@ -166,7 +392,96 @@ func (r *VaultConnectionReconciler) handleFinalizer(ctx context.Context, o *secr
 // SetupWithManager sets up the controller with the Manager.
 func (r *VaultConnectionReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&secretsv1alpha1.VaultConnection{}).
-		WithEventFilter(predicate.GenerationChangedPredicate{}).
+		For(&secretsv1alpha1.VaultConnection{},
+			builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Watches(&source.Kind{Type: &corev1.Secret{}},
+			handler.EnqueueRequestsFromMapFunc(r.mapSecret),
+			builder.WithPredicates(predicate.ResourceVersionChangedPredicate{})).
+		Watches(&source.Kind{Type: &corev1.ConfigMap{}},
+			handler.EnqueueRequestsFromMapFunc(r.mapConfigMap),
+			builder.WithPredicates(predicate.ResourceVersionChangedPredicate{})).
 		Complete(r)
 }
+
+// vaultHealth is the sys/health response of a single Vault address.
+type vaultHealth struct {
+	address       string
+	response      *api.HealthResponse
+	perfStandbyOK bool
+}
+
+// healthy returns true when Vault is initialized, unsealed, and active.
+func (h *vaultHealth) healthy() bool {
+	return h.response.Initialized && !h.response.Sealed &&
+		(!h.response.Standby || (h.perfStandbyOK && h.response.PerformanceStandby))
+}
+
+// setHealthConditions sets the Healthy, Sealed, and Standby conditions from the
+// vaultHealth, Sealed and Standby are Unknown when h is nil, since no address was reachable.
+func setHealthConditions(o *secretsv1alpha1.VaultConnection, h *vaultHealth) {
+	if h == nil {
+		msg := "No Vault address was reachable"
+		for _, c := range []metav1.Condition{
+			{Type: consts.TypeHealthy, Status: metav1.ConditionFalse},
+			{Type: consts.TypeSealed, Status: metav1.ConditionUnknown},
+			{Type: consts.TypeStandby, Status: metav1.ConditionUnknown},
+		} {
+			c.Reason = consts.ReasonVaultUnreachable
+			c.Message = msg
+			c.ObservedGeneration = o.Generation
+			meta.SetStatusCondition(&o.Status.Conditions, c)
+		}
+		return
+	}
+
+	resp := h.response
+	msg := fmt.Sprintf("Vault %s at %s, performance replication mode %q, DR replication mode %q",
+		resp.Version, h.address, resp.ReplicationPerformanceMode, resp.ReplicationDRMode)
+
+	healthy := metav1.Condition{
+		Type:   consts.TypeHealthy,
+		Status: metav1.ConditionTrue,
+		Reason: consts.ReasonVaultHealthy,
+	}
+	switch {
+	case !resp.Initialized:
+		healthy.Status = metav1.ConditionFalse
+		healthy.Reason = consts.ReasonVaultUninitialized
+	case resp.Sealed:
+		healthy.Status = metav1.ConditionFalse
+		healthy.Reason = consts.ReasonVaultSealed
+	case !h.healthy():
+		// a standby, unless it is a performance standby that is allowed to serve reads.
+		healthy.Status = metav1.ConditionFalse
+		healthy.Reason = consts.ReasonVaultStandby
+	}
+
+	sealed := metav1.Condition{
+		Type:   consts.TypeSealed,
+		Status: metav1.ConditionFalse,
+		Reason: consts.ReasonVaultUnsealed,
+	}
+	if resp.Sealed {
+		sealed.Status = metav1.ConditionTrue
+		sealed.Reason = consts.ReasonVaultSealed
+	}
+
+	standby := metav1.Condition{
+		Type:   consts.TypeStandby,
+		Status: metav1.ConditionFalse,
+		Reason: consts.ReasonVaultActive,
+	}
+	if resp.Standby {
+		standby.Status = metav1.ConditionTrue
+		standby.Reason = consts.ReasonVaultStandby
+	}
+
+	for _, c := range []metav1.Condition{healthy, sealed, standby} {
+		c.Message = msg
+		if c.Type == consts.TypeStandby && resp.PerformanceStandby {
+			c.Message += ", performance standby"
+		}
+		c.ObservedGeneration = o.Generation
+		meta.SetStatusCondition(&o.Status.Conditions, c)
+	}
+}
@@ -0,0 +1,167 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package controllers
+
+import (
+	"testing"
+
+	"github.com/hashicorp/vault/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	secretsv1alpha1 "github.com/hashicorp/vault-secrets-operator/api/v1alpha1"
+	"github.com/hashicorp/vault-secrets-operator/internal/consts"
+)
+
+func Test_setHealthConditions(t *testing.T) {
+	tests := map[string]struct {
+		health   *vaultHealth
+		expected map[string]metav1.ConditionStatus
+		reason   string
+	}{
+		"unreachable": {
+			health: nil,
+			expected: map[string]metav1.ConditionStatus{
+				consts.TypeHealthy: metav1.ConditionFalse,
+				consts.TypeSealed:  metav1.ConditionUnknown,
+				consts.TypeStandby: metav1.ConditionUnknown,
+			},
+			reason: consts.ReasonVaultUnreachable,
+		},
+		"active": {
+			health: &vaultHealth{
+				address: "https://vault-0:8200",
+				response: &api.HealthResponse{
+					Initialized: true,
+					Version:     "1.13.1",
+				},
+			},
+			expected: map[string]metav1.ConditionStatus{
+				consts.TypeHealthy: metav1.ConditionTrue,
+				consts.TypeSealed:  metav1.ConditionFalse,
+				consts.TypeStandby: metav1.ConditionFalse,
+			},
+			reason: consts.ReasonVaultHealthy,
+		},
+		"sealed": {
+			health: &vaultHealth{
+				address: "https://vault-0:8200",
+				response: &api.HealthResponse{
+					Initialized: true,
+					Sealed:      true,
+				},
+			},
+			expected: map[string]metav1.ConditionStatus{
+				consts.TypeHealthy: metav1.ConditionFalse,
+				consts.TypeSealed:  metav1.ConditionTrue,
+				consts.TypeStandby: metav1.ConditionFalse,
+			},
+			reason: consts.ReasonVaultSealed,
+		},
+		"standby": {
+			health: &vaultHealth{
+				address: "https://vault-0:8200",
+				response: &api.HealthResponse{
+					Initialized: true,
+					Standby:     true,
+				},
+			},
+			expected: map[string]metav1.ConditionStatus{
+				consts.TypeHealthy: metav1.ConditionFalse,
+				consts.TypeSealed:  metav1.ConditionFalse,
+				consts.TypeStandby: metav1.ConditionTrue,
+			},
+			reason: consts.ReasonVaultStandby,
+		},
+		"performance standby": {
+			health: &vaultHealth{
+				address: "https://vault-0:8200",
+				response: &api.HealthResponse{
+					Initialized:        true,
+					Standby:            true,
+					PerformanceStandby: true,
+				},
+				perfStandbyOK: true,
+			},
+			expected: map[string]metav1.ConditionStatus{
+				consts.TypeHealthy: metav1.ConditionTrue,
+				consts.TypeSealed:  metav1.ConditionFalse,
+				consts.TypeStandby: metav1.ConditionTrue,
+			},
+			reason: consts.ReasonVaultHealthy,
+		},
+		"performance standby not ok": {
+			health: &vaultHealth{
+				address: "https://vault-0:8200",
+				response: &api.HealthResponse{
+					Initialized:        true,
+					Standby:            true,
+					PerformanceStandby: true,
+				},
+			},
+			expected: map[string]metav1.ConditionStatus{
+				consts.TypeHealthy: metav1.ConditionFalse,
+				consts.TypeSealed:  metav1.ConditionFalse,
+				consts.TypeStandby: metav1.ConditionTrue,
+			},
+			reason: consts.ReasonVaultStandby,
+		},
+		"uninitialized": {
+			health: &vaultHealth{
+				address:  "https://vault-0:8200",
+				response: &api.HealthResponse{},
+			},
+			expected: map[string]metav1.ConditionStatus{
+				consts.TypeHealthy: metav1.ConditionFalse,
+				consts.TypeSealed:  metav1.ConditionFalse,
+				consts.TypeStandby: metav1.ConditionFalse,
+			},
+			reason: consts.ReasonVaultUninitialized,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			o := &secretsv1alpha1.VaultConnection{
+				ObjectMeta: metav1.ObjectMeta{
+					Generation: 2,
+				},
+			}
+			setHealthConditions(o, tc.health)
+
+			require.Len(t, o.Status.Conditions, len(tc.expected))
+			for conditionType, status := range tc.expected {
+				c := meta.FindStatusCondition(o.Status.Conditions, conditionType)
+				require.NotNil(t, c, conditionType)
+				assert.Equal(t, status, c.Status, conditionType)
+				assert.Equal(t, int64(2), c.ObservedGeneration, conditionType)
+			}
+			healthy := meta.FindStatusCondition(o.Status.Conditions, consts.TypeHealthy)
+			assert.Equal(t, tc.reason, healthy.Reason)
+		})
+	}
+}
+
+func Test_setHealthConditions_message(t *testing.T) {
+	o := &secretsv1alpha1.VaultConnection{}
+	setHealthConditions(o, &vaultHealth{
+		address: "https://vault-0:8200",
+		response: &api.HealthResponse{
+			Initialized:                true,
+			Standby:                    true,
+			PerformanceStandby:         true,
+			Version:                    "1.13.1+ent",
+			ReplicationPerformanceMode: "secondary",
+			ReplicationDRMode:          "disabled",
+		},
+	})
+
+	expected := `Vault 1.13.1+ent at https://vault-0:8200, ` +
+		`performance replication mode "secondary", DR replication mode "disabled"`
+	assert.Equal(t, expected, meta.FindStatusCondition(o.Status.Conditions, consts.TypeHealthy).Message)
+	assert.Equal(t, expected+", performance standby",
+		meta.FindStatusCondition(o.Status.Conditions, consts.TypeStandby).Message)
+}
@@ -5,6 +5,7 @@ package controllers
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"os"
@@ -29,9 +30,7 @@ import (
 	"github.com/hashicorp/vault-secrets-operator/internal/vault"
 )
 
-const (
-	vaultDynamicSecretFinalizer = "vaultdynamicsecret.secrets.hashicorp.com/finalizer"
-)
+const vaultDynamicSecretFinalizer = "vaultdynamicsecret.secrets.hashicorp.com/finalizer"
 
 // VaultDynamicSecretReconciler reconciles a VaultDynamicSecret object
 type VaultDynamicSecretReconciler struct {
@@ -49,6 +48,7 @@ type VaultDynamicSecretReconciler struct {
 //+kubebuilder:rbac:groups=secrets.hashicorp.com,resources=vaultdynamicsecrets/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=secrets.hashicorp.com,resources=vaultdynamicsecrets/finalizers,verbs=update
 //+kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+//+kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch
 //+kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create;update;patch
 //
 // required for rollout-restart
@@ -177,11 +177,31 @@ func (r *VaultDynamicSecretReconciler) Reconcile(ctx context.Context, req ctrl.R
 	}
 	oldLease := o.Status.SecretLease
 
-	secretLease, err := r.syncSecret(ctx, vClient, o)
+	var secretLease *secretsv1alpha1.VaultSecretLease
+	vClient, err = resumeControlGroupRequest(ctx, vClient, o, &o.Status.ControlGroupAccessor)
+	if err == nil {
+		secretLease, err = r.syncSecret(ctx, vClient, o)
+	}
 	if err != nil {
+		if handleControlGroupError(r.Recorder, o, &o.Status.ControlGroupAccessor, err) {
+			if err := r.updateStatus(ctx, o); err != nil {
+				return ctrl.Result{}, err
+			}
+			return ctrl.Result{RequeueAfter: controlGroupPollInterval}, nil
+		}
+
+		var tplErr *helpers.TemplateError
+		if errors.As(err, &tplErr) {
+			r.Recorder.Eventf(o, corev1.EventTypeWarning, consts.ReasonTemplateError, "%s", err)
+			helpers.SetTemplatesRenderedCondition(o, &o.Status.Conditions, err)
+			if err := r.updateStatus(ctx, o); err != nil {
+				return ctrl.Result{}, err
+			}
+		}
 		return ctrl.Result{}, err
 	}
 
+	helpers.SetTemplatesRenderedCondition(o, &o.Status.Conditions, nil)
 	o.Status.SecretLease = *secretLease
 	o.Status.LastRenewalTime = time.Now().Unix()
 	if err := r.updateStatus(ctx, o); err != nil {
@@ -237,8 +257,27 @@ func (r *VaultDynamicSecretReconciler) syncSecret(ctx context.Context, vClient v
 		return nil, err
 	}
 
+	// the credentials are never synced on error, so their lease should not
+	// outlive this attempt, otherwise every retry would leave another lease behind.
+	revokeOnErr := func(err error) error {
+		if resp.LeaseID != "" {
+			r.revokeLease(ctx, o, resp.LeaseID)
+		}
+		return err
+	}
+
+	data, err = helpers.TransformSecretData(o, data)
+	if err != nil {
+		return nil, revokeOnErr(err)
+	}
+
+	data, err = helpers.RenderTemplates(ctx, r.Client, o, helpers.NewTemplateInputFromSecret(resp), data)
+	if err != nil {
+		return nil, revokeOnErr(err)
+	}
+
 	if err := helpers.SyncSecret(ctx, r.Client, o, data); err != nil {
-		return nil, err
+		return nil, revokeOnErr(err)
 	}
 
 	return r.getVaultSecretLease(resp), nil
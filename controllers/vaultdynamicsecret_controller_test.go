@@ -0,0 +1,91 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	secretsv1alpha1 "github.com/hashicorp/vault-secrets-operator/api/v1alpha1"
+)
+
+func TestVaultDynamicSecretReconciler_syncSecret_revokeOnError(t *testing.T) {
+	tests := map[string]struct {
+		destination secretsv1alpha1.Destination
+	}{
+		"invalid transformation": {
+			destination: secretsv1alpha1.Destination{
+				Name:   "db",
+				Create: true,
+				Transformation: &secretsv1alpha1.Transformation{
+					Renames: map[string]string{"username": "invalid/key"},
+				},
+			},
+		},
+		"invalid template": {
+			destination: secretsv1alpha1.Destination{
+				Name:   "db",
+				Create: true,
+				Templates: map[string]string{
+					"dsn": "{{ .Secrets.username",
+				},
+			},
+		},
+		"sync error": {
+			destination: secretsv1alpha1.Destination{
+				Name: "db",
+			},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			s := runtime.NewScheme()
+			require.NoError(t, clientgoscheme.AddToScheme(s))
+			require.NoError(t, secretsv1alpha1.AddToScheme(s))
+
+			vc := &bundleVaultClient{
+				dynamic: map[string]map[string]any{
+					"db/creds/app": {"username": "user", "password": "pass"},
+				},
+			}
+			o := &secretsv1alpha1.VaultDynamicSecret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "db",
+					Namespace: "tenant-1",
+				},
+				Spec: secretsv1alpha1.VaultDynamicSecretSpec{
+					Mount:       "db",
+					Role:        "app",
+					Destination: tt.destination,
+				},
+			}
+			r := &VaultDynamicSecretReconciler{
+				Client:        fake.NewClientBuilder().WithScheme(s).WithObjects(o).Build(),
+				Scheme:        s,
+				Recorder:      record.NewFakeRecorder(100),
+				ClientFactory: &staticClientFactory{client: vc},
+			}
+
+			_, err := r.syncSecret(ctx, vc, o)
+			require.Error(t, err)
+			// the lease of the credentials that were never synced is revoked.
+			assert.Equal(t, []string{"db/creds/app/lease-1"}, vc.revoked)
+			var secret corev1.Secret
+			assert.True(t, apierrors.IsNotFound(
+				r.Get(ctx, client.ObjectKey{Namespace: "tenant-1", Name: "db"}, &secret)))
+		})
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/go-logr/logr"
+	"github.com/hashicorp/vault/api"
 	"github.com/operator-framework/operator-lib/handler"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
@@ -44,6 +45,7 @@ type VaultPKISecretReconciler struct {
 //+kubebuilder:rbac:groups=secrets.hashicorp.com,resources=vaultpkisecrets/finalizers,verbs=update
 //+kubebuilder:rbac:groups="",resources=secrets,verbs=get
 //+kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+//+kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch
 //
 // required for rollout-restart
 //+kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch;patch
@@ -155,8 +157,18 @@ func (r *VaultPKISecretReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 		return ctrl.Result{}, err
 	}
 
-	resp, err := c.Write(ctx, path, o.GetIssuerAPIData())
+	var resp *api.Secret
+	c, err = resumeControlGroupRequest(ctx, c, o, &o.Status.ControlGroupAccessor)
+	if err == nil {
+		resp, err = c.Write(ctx, path, o.GetIssuerAPIData())
+	}
 	if err != nil {
+		if handleControlGroupError(r.Recorder, o, &o.Status.ControlGroupAccessor, err) {
+			if err := r.updateStatus(ctx, o); err != nil {
+				return ctrl.Result{}, err
+			}
+			return ctrl.Result{RequeueAfter: controlGroupPollInterval}, nil
+		}
 		o.Status.Error = consts.ReasonK8sClientError
 		msg := "Failed to issue certificate from Vault"
 		logger.Error(err, msg)
@@ -218,6 +230,28 @@ func (r *VaultPKISecretReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 		data[corev1.TLSCertKey] = data["certificate"]
 		data[corev1.TLSPrivateKeyKey] = data["private_key"]
 	}
+	data, err = helpers.TransformSecretData(o, data)
+	if err != nil {
+		o.Status.Error = consts.ReasonInvalidConfiguration
+		msg := "Failed to transform Vault secret data"
+		logger.Error(err, msg)
+		r.recordEvent(o, o.Status.Error, msg+": %s", err)
+		if err := r.updateStatus(ctx, o); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{}, err
+	}
+	data, err = helpers.RenderTemplates(ctx, r.Client, o, helpers.NewTemplateInputFromSecret(resp), data)
+	helpers.SetTemplatesRenderedCondition(o, &o.Status.Conditions, err)
+	if err != nil {
+		o.Status.Error = consts.ReasonTemplateError
+		logger.Error(err, "Failed to render templates")
+		r.recordEvent(o, o.Status.Error, "%s", err)
+		if err := r.updateStatus(ctx, o); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{}, err
+	}
 	if err := helpers.SyncSecret(ctx, r.Client, o, data); err != nil {
 		return ctrl.Result{}, err
 	}
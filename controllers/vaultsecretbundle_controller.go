@@ -0,0 +1,665 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/vault/api"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	secretsv1alpha1 "github.com/hashicorp/vault-secrets-operator/api/v1alpha1"
+	"github.com/hashicorp/vault-secrets-operator/internal/consts"
+	"github.com/hashicorp/vault-secrets-operator/internal/helpers"
+	"github.com/hashicorp/vault-secrets-operator/internal/vault"
+)
+
+const vaultSecretBundleFinalizer = "vaultsecretbundle.secrets.hashicorp.com/finalizer"
+
+// VaultSecretBundleReconciler reconciles a VaultSecretBundle object
+type VaultSecretBundleReconciler struct {
+	client.Client
+	Scheme        *runtime.Scheme
+	Recorder      record.EventRecorder
+	ClientFactory vault.ClientFactory
+}
+
+//+kubebuilder:rbac:groups=secrets.hashicorp.com,resources=vaultsecretbundles,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=secrets.hashicorp.com,resources=vaultsecretbundles/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=secrets.hashicorp.com,resources=vaultsecretbundles/finalizers,verbs=update
+//+kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+//+kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch
+//+kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create;update;patch
+//
+// required for rollout-restart
+//+kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch;patch
+//+kubebuilder:rbac:groups=apps,resources=statefulsets,verbs=get;list;watch;patch
+//+kubebuilder:rbac:groups=apps,resources=daemonsets,verbs=get;list;watch;patch
+//
+
+// Reconcile ensures that the VaultSecretBundle's sources are merged into its Destination Secret.
+// Only the sources that are due for a refresh are read from Vault, the data of all other sources is
+// taken from the Destination Secret, so that every update of the Secret is a single atomic write.
+// Dynamic secret leases are renewed until the renewal fails, after which the secret is re-read.
+func (r *VaultSecretBundleReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	o := &secretsv1alpha1.VaultSecretBundle{}
+	if err := r.Client.Get(ctx, req.NamespacedName, o); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+
+		logger.Error(err, "error getting resource from k8s", "secret", o)
+		return ctrl.Result{}, err
+	}
+
+	if o.GetDeletionTimestamp() != nil {
+		logger.Info("Got deletion timestamp", "obj", o)
+		return ctrl.Result{}, r.handleDeletion(ctx, o)
+	}
+	// Add a finalizer if we intend to Revoke the leases of the dynamic sources on deletion.
+	if o.Spec.Revoke {
+		if err := r.addFinalizer(ctx, o); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	if err := validateBundleSources(o.Spec.Sources); err != nil {
+		logger.Error(err, "Invalid sources")
+		r.Recorder.Eventf(o, corev1.EventTypeWarning, consts.ReasonInvalidConfiguration,
+			"Invalid sources: %s", err)
+		return ctrl.Result{}, err
+	}
+
+	c, err := r.ClientFactory.Get(ctx, r.Client, o)
+	if err != nil {
+		r.Recorder.Eventf(o, corev1.EventTypeWarning, consts.ReasonVaultClientConfigError,
+			"Failed to get Vault auth login: %s", err)
+		return ctrl.Result{}, err
+	}
+
+	var cur map[string][]byte
+	if s, ok, err := helpers.GetSecret(ctx, r.Client, o); err != nil {
+		return ctrl.Result{}, err
+	} else if ok {
+		cur = s.Data
+	}
+
+	lastStatuses := makeBundleSourceStatusMap(o.Status.Sources)
+
+	// all sources must be synced whenever the spec has changed.
+	force := o.Status.ObservedGeneration != o.Generation
+	now := time.Now()
+	statuses := make([]secretsv1alpha1.VaultSecretBundleSourceStatus, len(o.Spec.Sources))
+	sourcesData := make([]map[string][]byte, len(o.Spec.Sources))
+	var synced []string
+	// the leases of all dynamic sources that were read, they are revoked
+	// if they are never recorded in the status.
+	var issued []string
+	defer func() {
+		if len(issued) > 0 {
+			r.revokeLeases(ctx, o, issued...)
+		}
+	}()
+	for i, src := range o.Spec.Sources {
+		last, hasLast := lastStatuses[src.Name]
+		data, complete := getBundleSourceData(cur, last.Keys)
+		if hasLast && !force && complete {
+			if !isBundleSourceDue(last, now) {
+				statuses[i], sourcesData[i] = last, data
+				continue
+			}
+
+			if src.Type == consts.BundleSourceTypeDynamic && last.SecretLease != nil && last.SecretLease.Renewable &&
+				last.ControlGroupAccessor == "" {
+				if st, err := r.renewBundleSourceLease(ctx, c, last, now); err == nil {
+					r.Recorder.Eventf(o, corev1.EventTypeNormal, consts.ReasonSecretLeaseRenewal,
+						"Renewed lease, source=%s, lease_id=%s", src.Name, st.SecretLease.ID)
+					statuses[i], sourcesData[i] = *st, data
+					continue
+				} else {
+					r.Recorder.Eventf(o, corev1.EventTypeWarning, consts.ReasonSecretLeaseRenewalError,
+						"Could not renew lease, source=%s, lease_id=%s, err=%s", src.Name, last.SecretLease.ID, err)
+				}
+			}
+		}
+
+		accessor := last.ControlGroupAccessor
+		sc, err := resumeControlGroupRequest(ctx, c, o, &accessor)
+		var st *secretsv1alpha1.VaultSecretBundleSourceStatus
+		if err == nil {
+			data, st, err = r.readBundleSource(ctx, sc, src, now)
+		}
+		if err == nil {
+			if st.SecretLease != nil && st.SecretLease.ID != "" {
+				issued = append(issued, st.SecretLease.ID)
+			}
+			// transforming each source's data ensures that the source's keys in the status
+			// match those in the Destination Secret.
+			data, err = helpers.TransformSecretData(o, data)
+		}
+		if err != nil {
+			if handleControlGroupError(r.Recorder, o, &accessor, err) {
+				// only the source's pending request is recorded, the Destination Secret is
+				// not synced until the request has been authorized.
+				setBundleSourceControlGroupAccessor(&o.Status, src.Name, accessor)
+				if err := r.Status().Update(ctx, o); err != nil {
+					return ctrl.Result{}, err
+				}
+				return ctrl.Result{RequeueAfter: controlGroupPollInterval}, nil
+			}
+			logger.Error(err, "Failed to read Vault secret", "source", src.Name)
+			r.Recorder.Eventf(o, corev1.EventTypeWarning, consts.ReasonVaultClientError,
+				"Failed to read Vault secret for source %s: %s", src.Name, err)
+			return ctrl.Result{}, err
+		}
+		statuses[i], sourcesData[i] = *st, data
+		synced = append(synced, src.Name)
+	}
+
+	data, keys, err := mergeBundleSourceData(o.Spec.ConflictPolicy, o.Spec.Sources, sourcesData)
+	if err != nil {
+		logger.Error(err, "Failed to merge sources")
+		r.Recorder.Eventf(o, corev1.EventTypeWarning, consts.ReasonSecretSyncError,
+			"Failed to merge sources: %s", err)
+		return ctrl.Result{}, err
+	}
+	for i := range statuses {
+		statuses[i].Keys = keys[i]
+	}
+
+	templates, err := helpers.LoadTemplates(ctx, r.Client, o)
+	if err == nil {
+		err = validateBundleTemplateKeys(templates, o.Spec.Sources, keys)
+	}
+	if err == nil {
+		input := helpers.NewTemplateInputFromData(data)
+		input.Metadata = makeBundleTemplateMetadata(statuses)
+		data, err = helpers.ExecuteTemplates(templates, input, data)
+	}
+	helpers.SetTemplatesRenderedCondition(o, &o.Status.Conditions, err)
+	if err != nil {
+		logger.Error(err, "Failed to render templates")
+		r.Recorder.Eventf(o, corev1.EventTypeWarning, consts.ReasonTemplateError, "%s", err)
+		if err := r.Status().Update(ctx, o); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{}, err
+	}
+
+	if cur == nil || !secretDataEqual(cur, data) {
+		if err := helpers.SyncSecret(ctx, r.Client, o, data); err != nil {
+			r.Recorder.Eventf(o, corev1.EventTypeWarning, consts.ReasonSecretSyncError,
+				"Failed to update k8s secret: %s", err)
+			return ctrl.Result{}, err
+		}
+
+		reason := consts.ReasonSecretSynced
+		// doRolloutRestart only if this is not the first time this secret has been synced
+		if o.Status.ObservedGeneration != 0 {
+			reason = consts.ReasonSecretRotated
+			// rollout-restart errors are not retryable
+			// all error reporting is handled by helpers.HandleRolloutRestarts
+			_ = helpers.HandleRolloutRestarts(ctx, r.Client, o, r.Recorder)
+		}
+		r.Recorder.Eventf(o, corev1.EventTypeNormal, reason,
+			"Secret synced, sources=%s", strings.Join(synced, ","))
+	} else if len(synced) > 0 {
+		r.Recorder.Eventf(o, corev1.EventTypeNormal, consts.ReasonSecretSync,
+			"Secret sync not required, sources=%s", strings.Join(synced, ","))
+	}
+
+	o.Status.Sources = statuses
+	o.Status.ObservedGeneration = o.Generation
+	if err := r.Status().Update(ctx, o); err != nil {
+		// the new leases are revoked, since they could never be revoked once their sources
+		// are re-read on the next reconcile.
+		return ctrl.Result{}, err
+	}
+	// the new leases are now recorded in the status.
+	issued = nil
+
+	if o.Spec.Revoke {
+		if superseded := getSupersededBundleLeases(lastStatuses, statuses); len(superseded) > 0 {
+			r.revokeLeases(ctx, o, superseded...)
+		}
+	}
+
+	return ctrl.Result{
+		RequeueAfter: computeBundleRequeueAfter(statuses, now),
+	}, nil
+}
+
+// readBundleSource reads the source's secret from Vault, returning its data with all keys prefixed
+// by the source's KeyPrefix, and the source's new status.
+func (r *VaultSecretBundleReconciler) readBundleSource(ctx context.Context, c vault.Client,
+	src secretsv1alpha1.VaultSecretBundleSource, now time.Time,
+) (map[string][]byte, *secretsv1alpha1.VaultSecretBundleSourceStatus, error) {
+	st := &secretsv1alpha1.VaultSecretBundleSourceStatus{
+		Name:         src.Name,
+		LastSyncTime: now.Unix(),
+	}
+
+	var data map[string][]byte
+	var refreshAfter time.Duration
+	switch src.Type {
+	case consts.KVSecretTypeV1, consts.KVSecretTypeV2:
+		resp, err := vault.ReadKVSecret(ctx, c, src.Type, src.Mount, src.Path, 0)
+		if err != nil {
+			return nil, nil, err
+		}
+		if resp == nil || resp.Data == nil {
+			return nil, nil, fmt.Errorf("empty Vault secret at %s", path.Join(src.Mount, src.Path))
+		}
+		data, err = makeK8sSecret(resp)
+		if err != nil {
+			return nil, nil, err
+		}
+		if resp.VersionMetadata != nil {
+			st.Version = resp.VersionMetadata.Version
+		}
+		if src.RefreshAfter != "" {
+			// already validated by validateBundleSources()
+			refreshAfter, _ = time.ParseDuration(src.RefreshAfter)
+		}
+	case consts.BundleSourceTypeDynamic:
+		p := path.Join(src.Mount, src.Path)
+		var resp *api.Secret
+		var err error
+		if len(src.Params) > 0 {
+			resp, err = c.Write(ctx, p, makeBundleSourceParams(src.Params))
+		} else {
+			resp, err = c.Read(ctx, p)
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		if resp == nil {
+			return nil, nil, fmt.Errorf("nil response from vault for path %s", p)
+		}
+		data, err = vault.MarshalSecretData(resp)
+		if err != nil {
+			return nil, nil, err
+		}
+		st.SecretLease = &secretsv1alpha1.VaultSecretLease{
+			ID:            resp.LeaseID,
+			LeaseDuration: resp.LeaseDuration,
+			Renewable:     resp.Renewable,
+			RequestID:     resp.RequestID,
+		}
+		refreshAfter = time.Duration(resp.LeaseDuration) * time.Second
+	case consts.BundleSourceTypePKI:
+		p := path.Join(src.Mount, "issue", src.Path)
+		resp, err := c.Write(ctx, p, makeBundleSourceParams(src.Params))
+		if err != nil {
+			return nil, nil, err
+		}
+		if resp == nil {
+			return nil, nil, fmt.Errorf("empty Vault secret at path %s", p)
+		}
+		certResp, err := vault.UnmarshalPKIIssueResponse(resp)
+		if err != nil {
+			return nil, nil, err
+		}
+		data, err = vault.MarshalSecretData(resp)
+		if err != nil {
+			return nil, nil, err
+		}
+		refreshAfter = time.Unix(certResp.Expiration, 0).Sub(now)
+	default:
+		return nil, nil, fmt.Errorf("unsupported source type %q", src.Type)
+	}
+
+	if refreshAfter > 0 {
+		st.NextSyncTime = now.Add(computeBundleHorizon(refreshAfter)).Unix()
+	}
+
+	// the raw secret is only meaningful for a single secret.
+	delete(data, "_raw")
+	prefixed := make(map[string][]byte, len(data))
+	for k, v := range data {
+		prefixed[src.KeyPrefix+k] = v
+	}
+
+	return prefixed, st, nil
+}
+
+// renewBundleSourceLease renews the dynamic secret lease of the source,
+// returning the source's new status.
+func (r *VaultSecretBundleReconciler) renewBundleSourceLease(ctx context.Context, c vault.Client,
+	last secretsv1alpha1.VaultSecretBundleSourceStatus, now time.Time,
+) (*secretsv1alpha1.VaultSecretBundleSourceStatus, error) {
+	resp, err := c.Write(ctx, "/sys/leases/renew", map[string]interface{}{
+		"lease_id":  last.SecretLease.ID,
+		"increment": last.SecretLease.LeaseDuration,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if resp == nil {
+		return nil, fmt.Errorf("nil response from vault for lease renewal")
+	}
+	if resp.LeaseID != last.SecretLease.ID {
+		return nil, fmt.Errorf("lease ID changed after renewal, expected=%s, actual=%s",
+			last.SecretLease.ID, resp.LeaseID)
+	}
+
+	st := last.DeepCopy()
+	st.SecretLease.LeaseDuration = resp.LeaseDuration
+	st.SecretLease.Renewable = resp.Renewable
+	st.NextSyncTime = 0
+	if resp.LeaseDuration > 0 {
+		st.NextSyncTime = now.Add(computeBundleHorizon(time.Duration(resp.LeaseDuration) * time.Second)).Unix()
+	}
+	return st, nil
+}
+
+// getSupersededBundleLeases returns the IDs of the leases in last that are no longer part of statuses,
+// either because the source's secret was re-read, or because the source was removed.
+func getSupersededBundleLeases(last map[string]secretsv1alpha1.VaultSecretBundleSourceStatus,
+	statuses []secretsv1alpha1.VaultSecretBundleSourceStatus,
+) []string {
+	current := make(map[string]bool, len(statuses))
+	for _, st := range statuses {
+		if st.SecretLease != nil {
+			current[st.SecretLease.ID] = true
+		}
+	}
+
+	var ids []string
+	for _, st := range last {
+		if st.SecretLease != nil && st.SecretLease.ID != "" && !current[st.SecretLease.ID] {
+			ids = append(ids, st.SecretLease.ID)
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func (r *VaultSecretBundleReconciler) addFinalizer(ctx context.Context, o *secretsv1alpha1.VaultSecretBundle) error {
+	if !controllerutil.ContainsFinalizer(o, vaultSecretBundleFinalizer) {
+		controllerutil.AddFinalizer(o, vaultSecretBundleFinalizer)
+		if err := r.Client.Update(ctx, o); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// handleDeletion will handle the deletion path of the VaultSecretBundle:
+// * revoking the leases of all dynamic sources, if Revoke is set
+// * removing our finalizer
+func (r *VaultSecretBundleReconciler) handleDeletion(ctx context.Context, o *secretsv1alpha1.VaultSecretBundle) error {
+	logger := log.FromContext(ctx)
+	if !controllerutil.ContainsFinalizer(o, vaultSecretBundleFinalizer) {
+		return nil
+	}
+
+	// We are ignoring errors inside `revokeLeases`, otherwise we may fail to remove the finalizer.
+	// Worst case at this point we will leave dangling leases. Events are emitted in these cases.
+	if o.Spec.Revoke {
+		if ids := getSupersededBundleLeases(
+			makeBundleSourceStatusMap(o.Status.Sources), nil); len(ids) > 0 {
+			r.revokeLeases(ctx, o, ids...)
+		}
+	}
+
+	logger.Info("Removing finalizer")
+	if controllerutil.RemoveFinalizer(o, vaultSecretBundleFinalizer) {
+		if err := r.Update(ctx, o); err != nil {
+			logger.Error(err, "Failed to remove the finalizer")
+			return err
+		}
+		logger.Info("Successfully removed the finalizer")
+	}
+	return nil
+}
+
+// revokeLeases revokes the dynamic secret leases identified by ids.
+// NOTE: Enabling revocation requires the VaultAuthMethod referenced by `o.Spec.VaultAuthRef` to have a policy
+// that includes `path "sys/leases/revoke" { capabilities = ["update"] }`, otherwise this will fail with permission
+// errors.
+func (r *VaultSecretBundleReconciler) revokeLeases(ctx context.Context, o *secretsv1alpha1.VaultSecretBundle, ids ...string) {
+	logger := log.FromContext(ctx)
+	c, err := r.ClientFactory.Get(ctx, r.Client, o)
+	if err != nil {
+		logger.Error(err, "Failed to get client when revoking leases", "ids", ids)
+		return
+	}
+
+	for _, id := range ids {
+		logger.Info("Revoking lease for credential ", "id", id)
+		if _, err := c.Write(ctx, "/sys/leases/revoke", map[string]interface{}{
+			"lease_id": id,
+		}); err != nil {
+			r.Recorder.Eventf(o, corev1.EventTypeWarning, consts.ReasonSecretLeaseRevoke,
+				"Failed to revoke lease: %s", err)
+			logger.Error(err, "Failed to revoke lease ", "id", id)
+		} else {
+			r.Recorder.Eventf(o, corev1.EventTypeNormal, consts.ReasonSecretLeaseRevoke,
+				"Lease revoked: %s", id)
+			logger.Info("Lease revoked ", "id", id)
+		}
+	}
+}
+
+// makeBundleTemplateMetadata returns the template metadata of each source, keyed by the
+// source's name. It is derived from the sources' statuses, so that it is the same whether
+// or not the source was read from Vault.
+func makeBundleTemplateMetadata(statuses []secretsv1alpha1.VaultSecretBundleSourceStatus) map[string]any {
+	m := make(map[string]any, len(statuses))
+	for _, st := range statuses {
+		sm := map[string]any{}
+		if l := st.SecretLease; l != nil {
+			sm["lease_id"] = l.ID
+			sm["lease_duration"] = l.LeaseDuration
+			sm["renewable"] = l.Renewable
+			sm["request_id"] = l.RequestID
+		}
+		if st.Version > 0 {
+			sm["version"] = st.Version
+		}
+		m[st.Name] = sm
+	}
+	return m
+}
+
+func makeBundleSourceStatusMap(statuses []secretsv1alpha1.VaultSecretBundleSourceStatus) map[string]secretsv1alpha1.VaultSecretBundleSourceStatus {
+	m := make(map[string]secretsv1alpha1.VaultSecretBundleSourceStatus, len(statuses))
+	for _, s := range statuses {
+		m[s.Name] = s
+	}
+	return m
+}
+
+// validateBundleTemplateKeys ensures that none of the templates render a key that is
+// provided by one of the sources. The data of a source that is not due for a refresh is
+// taken from the Destination Secret, so a rendered value would otherwise be taken for
+// the source's data, and be rendered again.
+func validateBundleTemplateKeys(templates map[string]string,
+	sources []secretsv1alpha1.VaultSecretBundleSource, keys [][]string,
+) error {
+	for i, src := range sources {
+		for _, k := range keys[i] {
+			if _, ok := templates[k]; ok {
+				return &helpers.TemplateError{
+					Key: k,
+					Err: fmt.Errorf("key is provided by source %q", src.Name),
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// validateBundleSources ensures that the sources can be synced.
+func validateBundleSources(sources []secretsv1alpha1.VaultSecretBundleSource) error {
+	names := make(map[string]bool, len(sources))
+	for _, src := range sources {
+		if names[src.Name] {
+			return fmt.Errorf("duplicate source name %q", src.Name)
+		}
+		names[src.Name] = true
+
+		switch src.Type {
+		case consts.KVSecretTypeV1, consts.KVSecretTypeV2:
+			if len(src.Params) > 0 {
+				return fmt.Errorf("params are not supported for source %q of type %q", src.Name, src.Type)
+			}
+			if src.RefreshAfter != "" {
+				if _, err := time.ParseDuration(src.RefreshAfter); err != nil {
+					return fmt.Errorf("invalid refreshAfter for source %q: %w", src.Name, err)
+				}
+			}
+		case consts.BundleSourceTypeDynamic, consts.BundleSourceTypePKI:
+			if src.RefreshAfter != "" {
+				return fmt.Errorf("refreshAfter is not supported for source %q of type %q", src.Name, src.Type)
+			}
+		default:
+			return fmt.Errorf("unsupported type %q for source %q", src.Type, src.Name)
+		}
+	}
+	return nil
+}
+
+// getBundleSourceData returns the data for keys from the Destination Secret's data cur,
+// along with whether all the keys were found. It returns false if there are no keys.
+func getBundleSourceData(cur map[string][]byte, keys []string) (map[string][]byte, bool) {
+	if len(keys) == 0 {
+		return nil, false
+	}
+
+	data := make(map[string][]byte, len(keys))
+	for _, k := range keys {
+		v, ok := cur[k]
+		if !ok {
+			return nil, false
+		}
+		data[k] = v
+	}
+	return data, true
+}
+
+// mergeBundleSourceData merges the data of all sources according to the conflict policy.
+// The keys contributed by each source to the merged data are returned.
+func mergeBundleSourceData(policy string, sources []secretsv1alpha1.VaultSecretBundleSource,
+	sourcesData []map[string][]byte,
+) (map[string][]byte, [][]string, error) {
+	data := make(map[string][]byte)
+	owners := make(map[string]int)
+	for i, sd := range sourcesData {
+		keys := make([]string, 0, len(sd))
+		for k := range sd {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		for _, k := range keys {
+			if j, ok := owners[k]; ok {
+				switch policy {
+				case consts.ConflictPolicyFirstWins:
+					continue
+				case consts.ConflictPolicyLastWins:
+				default:
+					return nil, nil, fmt.Errorf("key %q is provided by both sources %q and %q",
+						k, sources[j].Name, sources[i].Name)
+				}
+			}
+			owners[k] = i
+			data[k] = sd[k]
+		}
+	}
+
+	keys := make([][]string, len(sourcesData))
+	for k, i := range owners {
+		keys[i] = append(keys[i], k)
+	}
+	for i := range keys {
+		sort.Strings(keys[i])
+	}
+
+	return data, keys, nil
+}
+
+// setBundleSourceControlGroupAccessor sets the ControlGroupAccessor of the named source's status.
+func setBundleSourceControlGroupAccessor(status *secretsv1alpha1.VaultSecretBundleStatus, name, accessor string) {
+	for i := range status.Sources {
+		if status.Sources[i].Name == name {
+			status.Sources[i].ControlGroupAccessor = accessor
+			return
+		}
+	}
+	status.Sources = append(status.Sources, secretsv1alpha1.VaultSecretBundleSourceStatus{
+		Name:                 name,
+		ControlGroupAccessor: accessor,
+	})
+}
+
+func makeBundleSourceParams(params map[string]string) map[string]any {
+	m := make(map[string]any, len(params))
+	for k, v := range params {
+		m[k] = v
+	}
+	return m
+}
+
+func isBundleSourceDue(st secretsv1alpha1.VaultSecretBundleSourceStatus, now time.Time) bool {
+	return st.NextSyncTime > 0 && now.Unix() >= st.NextSyncTime
+}
+
+// computeBundleHorizon returns the jittered horizon for d, it is never less than one second.
+func computeBundleHorizon(d time.Duration) time.Duration {
+	if horizon := computeHorizonWithJitter(d); horizon > time.Second {
+		return horizon
+	}
+	return time.Second
+}
+
+// computeBundleRequeueAfter returns the duration until the next source is due,
+// zero is returned if no source will ever be due.
+func computeBundleRequeueAfter(statuses []secretsv1alpha1.VaultSecretBundleSourceStatus, now time.Time) time.Duration {
+	var next int64
+	for _, st := range statuses {
+		if st.NextSyncTime > 0 && (next == 0 || st.NextSyncTime < next) {
+			next = st.NextSyncTime
+		}
+	}
+	if next == 0 {
+		return 0
+	}
+
+	if d := time.Unix(next, 0).Sub(now); d > time.Second {
+		return d
+	}
+	return time.Second
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *VaultSecretBundleReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&secretsv1alpha1.VaultSecretBundle{},
+			builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		// the templates are rendered on every sync.
+		Watches(&source.Kind{Type: &corev1.ConfigMap{}},
+			handler.EnqueueRequestsFromMapFunc(mapTemplatesConfigMap(r.Client, &secretsv1alpha1.VaultSecretBundleList{})),
+			builder.WithPredicates(predicate.ResourceVersionChangedPredicate{})).
+		Complete(r)
+}
@@ -0,0 +1,699 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/vault/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	secretsv1alpha1 "github.com/hashicorp/vault-secrets-operator/api/v1alpha1"
+	"github.com/hashicorp/vault-secrets-operator/internal/consts"
+	"github.com/hashicorp/vault-secrets-operator/internal/vault"
+)
+
+func Test_mergeBundleSourceData(t *testing.T) {
+	sources := []secretsv1alpha1.VaultSecretBundleSource{
+		{Name: "app"},
+		{Name: "db"},
+	}
+	sourcesData := []map[string][]byte{
+		{
+			"host":  []byte("app.example.com"),
+			"token": []byte("app-token"),
+		},
+		{
+			"host":     []byte("db.example.com"),
+			"password": []byte("db-password"),
+		},
+	}
+
+	tests := map[string]struct {
+		policy       string
+		sourcesData  []map[string][]byte
+		expectedData map[string][]byte
+		expectedKeys [][]string
+		wantErr      string
+	}{
+		"no conflict": {
+			policy: consts.ConflictPolicyError,
+			sourcesData: []map[string][]byte{
+				{"token": []byte("app-token")},
+				{"password": []byte("db-password")},
+			},
+			expectedData: map[string][]byte{
+				"token":    []byte("app-token"),
+				"password": []byte("db-password"),
+			},
+			expectedKeys: [][]string{{"token"}, {"password"}},
+		},
+		"conflict error": {
+			policy:      consts.ConflictPolicyError,
+			sourcesData: sourcesData,
+			wantErr:     `key "host" is provided by both sources "app" and "db"`,
+		},
+		"conflict default policy": {
+			sourcesData: sourcesData,
+			wantErr:     `key "host" is provided by both sources "app" and "db"`,
+		},
+		"conflict firstWins": {
+			policy:      consts.ConflictPolicyFirstWins,
+			sourcesData: sourcesData,
+			expectedData: map[string][]byte{
+				"host":     []byte("app.example.com"),
+				"token":    []byte("app-token"),
+				"password": []byte("db-password"),
+			},
+			expectedKeys: [][]string{{"host", "token"}, {"password"}},
+		},
+		"conflict lastWins": {
+			policy:      consts.ConflictPolicyLastWins,
+			sourcesData: sourcesData,
+			expectedData: map[string][]byte{
+				"host":     []byte("db.example.com"),
+				"token":    []byte("app-token"),
+				"password": []byte("db-password"),
+			},
+			expectedKeys: [][]string{{"token"}, {"host", "password"}},
+		},
+		"empty source": {
+			policy: consts.ConflictPolicyError,
+			sourcesData: []map[string][]byte{
+				{},
+				{"password": []byte("db-password")},
+			},
+			expectedData: map[string][]byte{
+				"password": []byte("db-password"),
+			},
+			expectedKeys: [][]string{nil, {"password"}},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			data, keys, err := mergeBundleSourceData(tt.policy, sources, tt.sourcesData)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedData, data)
+			assert.Equal(t, tt.expectedKeys, keys)
+		})
+	}
+}
+
+func Test_validateBundleSources(t *testing.T) {
+	tests := map[string]struct {
+		sources []secretsv1alpha1.VaultSecretBundleSource
+		wantErr string
+	}{
+		"valid": {
+			sources: []secretsv1alpha1.VaultSecretBundleSource{
+				{Name: "app", Type: consts.KVSecretTypeV2, RefreshAfter: "30s"},
+				{Name: "db", Type: consts.BundleSourceTypeDynamic, Params: map[string]string{"ttl": "1h"}},
+				{Name: "tls", Type: consts.BundleSourceTypePKI, Params: map[string]string{"common_name": "foo"}},
+			},
+		},
+		"duplicate name": {
+			sources: []secretsv1alpha1.VaultSecretBundleSource{
+				{Name: "app", Type: consts.KVSecretTypeV2},
+				{Name: "app", Type: consts.KVSecretTypeV1},
+			},
+			wantErr: `duplicate source name "app"`,
+		},
+		"kv with params": {
+			sources: []secretsv1alpha1.VaultSecretBundleSource{
+				{Name: "app", Type: consts.KVSecretTypeV1, Params: map[string]string{"foo": "bar"}},
+			},
+			wantErr: `params are not supported for source "app" of type "kv-v1"`,
+		},
+		"kv invalid refreshAfter": {
+			sources: []secretsv1alpha1.VaultSecretBundleSource{
+				{Name: "app", Type: consts.KVSecretTypeV2, RefreshAfter: "soon"},
+			},
+			wantErr: `invalid refreshAfter for source "app": time: invalid duration "soon"`,
+		},
+		"dynamic with refreshAfter": {
+			sources: []secretsv1alpha1.VaultSecretBundleSource{
+				{Name: "db", Type: consts.BundleSourceTypeDynamic, RefreshAfter: "30s"},
+			},
+			wantErr: `refreshAfter is not supported for source "db" of type "dynamic"`,
+		},
+		"unsupported type": {
+			sources: []secretsv1alpha1.VaultSecretBundleSource{
+				{Name: "app", Type: "transit"},
+			},
+			wantErr: `unsupported type "transit" for source "app"`,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := validateBundleSources(tt.sources)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func Test_getBundleSourceData(t *testing.T) {
+	cur := map[string][]byte{
+		"token":    []byte("app-token"),
+		"password": []byte("db-password"),
+	}
+
+	tests := map[string]struct {
+		keys         []string
+		expectedData map[string][]byte
+		expectedOK   bool
+	}{
+		"all keys": {
+			keys: []string{"token"},
+			expectedData: map[string][]byte{
+				"token": []byte("app-token"),
+			},
+			expectedOK: true,
+		},
+		"missing key": {
+			keys:       []string{"token", "username"},
+			expectedOK: false,
+		},
+		"no keys": {
+			expectedOK: false,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			data, ok := getBundleSourceData(cur, tt.keys)
+			assert.Equal(t, tt.expectedOK, ok)
+			assert.Equal(t, tt.expectedData, data)
+		})
+	}
+}
+
+func Test_computeBundleRequeueAfter(t *testing.T) {
+	now := time.Unix(1000, 0)
+	tests := map[string]struct {
+		statuses []secretsv1alpha1.VaultSecretBundleSourceStatus
+		expected time.Duration
+	}{
+		"never due": {
+			statuses: []secretsv1alpha1.VaultSecretBundleSourceStatus{
+				{Name: "app"},
+			},
+			expected: 0,
+		},
+		"earliest": {
+			statuses: []secretsv1alpha1.VaultSecretBundleSourceStatus{
+				{Name: "app", NextSyncTime: 1300},
+				{Name: "db"},
+				{Name: "tls", NextSyncTime: 1060},
+			},
+			expected: time.Minute,
+		},
+		"overdue": {
+			statuses: []secretsv1alpha1.VaultSecretBundleSourceStatus{
+				{Name: "app", NextSyncTime: 900},
+			},
+			expected: time.Second,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, computeBundleRequeueAfter(tt.statuses, now))
+		})
+	}
+}
+
+// bundleVaultClient is a vault.Client that serves the secrets of a VaultSecretBundle's sources.
+// Each read of a dynamic secret returns a new lease, all requests are recorded.
+type bundleVaultClient struct {
+	vault.Client
+	// kv maps a kv-v2 path to its secret data.
+	kv map[string]map[string]any
+	// dynamic maps the path of a dynamic secret to its secret data.
+	dynamic  map[string]map[string]any
+	renewErr error
+	leases   int
+	requests []string
+	revoked  []string
+}
+
+func (c *bundleVaultClient) Read(_ context.Context, p string) (*api.Secret, error) {
+	c.requests = append(c.requests, "read "+p)
+	if data, ok := c.kv[p]; ok {
+		return &api.Secret{Data: map[string]any{
+			"data":     data,
+			"metadata": map[string]any{"version": json.Number("1")},
+		}}, nil
+	}
+	if data, ok := c.dynamic[p]; ok {
+		c.leases++
+		return &api.Secret{
+			LeaseID:       fmt.Sprintf("%s/lease-%d", p, c.leases),
+			LeaseDuration: 3600,
+			Renewable:     true,
+			Data:          data,
+		}, nil
+	}
+	return nil, nil
+}
+
+func (c *bundleVaultClient) Write(_ context.Context, p string, m map[string]any) (*api.Secret, error) {
+	c.requests = append(c.requests, "write "+p)
+	switch p {
+	case "/sys/leases/renew":
+		if c.renewErr != nil {
+			return nil, c.renewErr
+		}
+		return &api.Secret{
+			LeaseID:       m["lease_id"].(string),
+			LeaseDuration: 3600,
+			Renewable:     true,
+		}, nil
+	case "/sys/leases/revoke":
+		c.revoked = append(c.revoked, m["lease_id"].(string))
+		return nil, nil
+	}
+	return nil, fmt.Errorf("unexpected write to %s", p)
+}
+
+type staticClientFactory struct {
+	client vault.Client
+}
+
+func (f *staticClientFactory) Get(context.Context, client.Client, client.Object) (vault.Client, error) {
+	return f.client, nil
+}
+
+func newBundleReconciler(t *testing.T, vc vault.Client, objs ...client.Object) *VaultSecretBundleReconciler {
+	t.Helper()
+
+	s := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(s))
+	require.NoError(t, secretsv1alpha1.AddToScheme(s))
+
+	return &VaultSecretBundleReconciler{
+		Client:        fake.NewClientBuilder().WithScheme(s).WithObjects(objs...).Build(),
+		Scheme:        s,
+		Recorder:      record.NewFakeRecorder(100),
+		ClientFactory: &staticClientFactory{client: vc},
+	}
+}
+
+func newTestBundle(sources ...secretsv1alpha1.VaultSecretBundleSource) *secretsv1alpha1.VaultSecretBundle {
+	return &secretsv1alpha1.VaultSecretBundle{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "bundle",
+			Namespace:  "tenant-1",
+			Generation: 1,
+		},
+		Spec: secretsv1alpha1.VaultSecretBundleSpec{
+			Sources: sources,
+			Destination: secretsv1alpha1.Destination{
+				Name:   "bundle",
+				Create: true,
+			},
+		},
+	}
+}
+
+func getBundleSecretData(t *testing.T, r *VaultSecretBundleReconciler) map[string]string {
+	t.Helper()
+
+	var s corev1.Secret
+	require.NoError(t, r.Get(context.Background(), client.ObjectKey{Namespace: "tenant-1", Name: "bundle"}, &s))
+	data := make(map[string]string, len(s.Data))
+	for k, v := range s.Data {
+		data[k] = string(v)
+	}
+	return data
+}
+
+// setBundleSourceDue makes the named source due for a refresh.
+func setBundleSourceDue(t *testing.T, r *VaultSecretBundleReconciler, name string) {
+	t.Helper()
+
+	ctx := context.Background()
+	var o secretsv1alpha1.VaultSecretBundle
+	require.NoError(t, r.Get(ctx, client.ObjectKey{Namespace: "tenant-1", Name: "bundle"}, &o))
+	for i := range o.Status.Sources {
+		if o.Status.Sources[i].Name == name {
+			o.Status.Sources[i].NextSyncTime = time.Now().Add(-time.Second).Unix()
+		}
+	}
+	require.NoError(t, r.Status().Update(ctx, &o))
+}
+
+func TestVaultSecretBundleReconciler_Reconcile_scheduling(t *testing.T) {
+	ctx := context.Background()
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "tenant-1", Name: "bundle"}}
+
+	vc := &bundleVaultClient{
+		kv: map[string]map[string]any{
+			"kv/data/app": {"api_key": "key-1"},
+		},
+		dynamic: map[string]map[string]any{
+			"db/creds/app": {"username": "user", "password": "pass"},
+		},
+	}
+	o := newTestBundle(
+		secretsv1alpha1.VaultSecretBundleSource{
+			Name:         "app",
+			Type:         consts.KVSecretTypeV2,
+			Mount:        "kv",
+			Path:         "app",
+			RefreshAfter: "1h",
+		},
+		secretsv1alpha1.VaultSecretBundleSource{
+			Name:      "db",
+			Type:      consts.BundleSourceTypeDynamic,
+			Mount:     "db",
+			Path:      "creds/app",
+			KeyPrefix: "db_",
+		},
+	)
+	o.Spec.Revoke = true
+	r := newBundleReconciler(t, vc, o)
+
+	// all sources are read on the first sync.
+	result, err := r.Reconcile(ctx, req)
+	require.NoError(t, err)
+	assert.Greater(t, result.RequeueAfter, time.Duration(0))
+	assert.Equal(t, []string{"read kv/data/app", "read db/creds/app"}, vc.requests)
+	assert.Equal(t, map[string]string{
+		"api_key":     "key-1",
+		"db_username": "user",
+		"db_password": "pass",
+	}, getBundleSecretData(t, r))
+
+	var got secretsv1alpha1.VaultSecretBundle
+	require.NoError(t, r.Get(ctx, req.NamespacedName, &got))
+	assert.Contains(t, got.Finalizers, vaultSecretBundleFinalizer)
+	require.Len(t, got.Status.Sources, 2)
+	assert.Equal(t, []string{"api_key"}, got.Status.Sources[0].Keys)
+	assert.Equal(t, []string{"db_password", "db_username"}, got.Status.Sources[1].Keys)
+	assert.Equal(t, "db/creds/app/lease-1", got.Status.Sources[1].SecretLease.ID)
+
+	// no source is due.
+	vc.requests = nil
+	_, err = r.Reconcile(ctx, req)
+	require.NoError(t, err)
+	assert.Empty(t, vc.requests)
+
+	// only the due source's lease is renewed.
+	setBundleSourceDue(t, r, "db")
+	_, err = r.Reconcile(ctx, req)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"write /sys/leases/renew"}, vc.requests)
+	assert.Empty(t, vc.revoked)
+
+	// the source is re-read once its lease can no longer be renewed,
+	// the superseded lease is revoked.
+	vc.requests = nil
+	vc.renewErr = errors.New("lease expired")
+	vc.dynamic["db/creds/app"] = map[string]any{"username": "user-2", "password": "pass-2"}
+	setBundleSourceDue(t, r, "db")
+	_, err = r.Reconcile(ctx, req)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"write /sys/leases/renew",
+		"read db/creds/app",
+		"write /sys/leases/revoke",
+	}, vc.requests)
+	assert.Equal(t, []string{"db/creds/app/lease-1"}, vc.revoked)
+	assert.Equal(t, map[string]string{
+		"api_key":     "key-1",
+		"db_username": "user-2",
+		"db_password": "pass-2",
+	}, getBundleSecretData(t, r))
+
+	// the kv source is refreshed on its own schedule.
+	vc.requests = nil
+	vc.kv["kv/data/app"] = map[string]any{"api_key": "key-2"}
+	setBundleSourceDue(t, r, "app")
+	_, err = r.Reconcile(ctx, req)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"read kv/data/app"}, vc.requests)
+	assert.Equal(t, "key-2", getBundleSecretData(t, r)["api_key"])
+
+	// the lease of a removed source is revoked.
+	vc.requests = nil
+	require.NoError(t, r.Get(ctx, req.NamespacedName, &got))
+	got.Spec.Sources = got.Spec.Sources[:1]
+	got.Generation++
+	require.NoError(t, r.Update(ctx, &got))
+	_, err = r.Reconcile(ctx, req)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"db/creds/app/lease-1", "db/creds/app/lease-2"}, vc.revoked)
+	assert.Equal(t, map[string]string{"api_key": "key-2"}, getBundleSecretData(t, r))
+}
+
+func TestVaultSecretBundleReconciler_Reconcile_deletion(t *testing.T) {
+	ctx := context.Background()
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "tenant-1", Name: "bundle"}}
+
+	vc := &bundleVaultClient{
+		dynamic: map[string]map[string]any{
+			"db/creds/app": {"username": "user"},
+		},
+	}
+	o := newTestBundle(secretsv1alpha1.VaultSecretBundleSource{
+		Name:  "db",
+		Type:  consts.BundleSourceTypeDynamic,
+		Mount: "db",
+		Path:  "creds/app",
+	})
+	o.Spec.Revoke = true
+	r := newBundleReconciler(t, vc, o)
+
+	_, err := r.Reconcile(ctx, req)
+	require.NoError(t, err)
+
+	var got secretsv1alpha1.VaultSecretBundle
+	require.NoError(t, r.Get(ctx, req.NamespacedName, &got))
+	require.NoError(t, r.Delete(ctx, &got))
+
+	_, err = r.Reconcile(ctx, req)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"db/creds/app/lease-1"}, vc.revoked)
+	assert.True(t, apierrors.IsNotFound(r.Get(ctx, req.NamespacedName, &got)))
+}
+
+func TestVaultSecretBundleReconciler_Reconcile_conflictPolicy(t *testing.T) {
+	tests := map[string]struct {
+		policy   string
+		expected map[string]string
+		wantErr  string
+	}{
+		"error": {
+			policy:  consts.ConflictPolicyError,
+			wantErr: `key "username" is provided by both sources "app" and "db"`,
+		},
+		"first-wins": {
+			policy: consts.ConflictPolicyFirstWins,
+			expected: map[string]string{
+				"username": "app-user",
+				"password": "db-pass",
+			},
+		},
+		"last-wins": {
+			policy: consts.ConflictPolicyLastWins,
+			expected: map[string]string{
+				"username": "db-user",
+				"password": "db-pass",
+			},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			req := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "tenant-1", Name: "bundle"}}
+
+			vc := &bundleVaultClient{
+				kv: map[string]map[string]any{
+					"kv/data/app": {"username": "app-user"},
+				},
+				dynamic: map[string]map[string]any{
+					"db/creds/app": {"username": "db-user", "password": "db-pass"},
+				},
+			}
+			o := newTestBundle(
+				secretsv1alpha1.VaultSecretBundleSource{
+					Name:  "app",
+					Type:  consts.KVSecretTypeV2,
+					Mount: "kv",
+					Path:  "app",
+				},
+				secretsv1alpha1.VaultSecretBundleSource{
+					Name:  "db",
+					Type:  consts.BundleSourceTypeDynamic,
+					Mount: "db",
+					Path:  "creds/app",
+				},
+			)
+			o.Spec.ConflictPolicy = tt.policy
+			r := newBundleReconciler(t, vc, o)
+
+			_, err := r.Reconcile(ctx, req)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				// the lease of the credentials that were never synced is revoked.
+				assert.Equal(t, []string{"db/creds/app/lease-1"}, vc.revoked)
+				var s corev1.Secret
+				assert.True(t, apierrors.IsNotFound(
+					r.Get(ctx, client.ObjectKey{Namespace: "tenant-1", Name: "bundle"}, &s)))
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, getBundleSecretData(t, r))
+			assert.Empty(t, vc.revoked)
+		})
+	}
+}
+
+func TestVaultSecretBundleReconciler_Reconcile_templates(t *testing.T) {
+	ctx := context.Background()
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "tenant-1", Name: "bundle"}}
+
+	vc := &bundleVaultClient{
+		kv: map[string]map[string]any{
+			"kv/data/app": {"password": "pass"},
+		},
+		dynamic: map[string]map[string]any{
+			"db/creds/app": {"username": "user"},
+		},
+	}
+	o := newTestBundle(
+		secretsv1alpha1.VaultSecretBundleSource{
+			Name:  "app",
+			Type:  consts.KVSecretTypeV2,
+			Mount: "kv",
+			Path:  "app",
+		},
+		secretsv1alpha1.VaultSecretBundleSource{
+			Name:  "db",
+			Type:  consts.BundleSourceTypeDynamic,
+			Mount: "db",
+			Path:  "creds/app",
+		},
+	)
+	o.Spec.Destination.Templates = map[string]string{
+		"password": "{{ .Secrets.password | b64enc }}",
+	}
+	r := newBundleReconciler(t, vc, o)
+
+	// a template must not replace a source's key, since the source's data would
+	// otherwise be taken from the rendered value whenever the source is not due.
+	_, err := r.Reconcile(ctx, req)
+	assert.EqualError(t, err, `failed to render template for key "password": key is provided by source "app"`)
+	assert.Equal(t, []string{"db/creds/app/lease-1"}, vc.revoked)
+	var s corev1.Secret
+	assert.True(t, apierrors.IsNotFound(
+		r.Get(ctx, client.ObjectKey{Namespace: "tenant-1", Name: "bundle"}, &s)))
+
+	var got secretsv1alpha1.VaultSecretBundle
+	require.NoError(t, r.Get(ctx, req.NamespacedName, &got))
+	got.Spec.Destination.Templates = map[string]string{
+		"password_b64": "{{ .Secrets.password | b64enc }}",
+		"metadata":     "app={{ .Metadata.app.version }},db={{ .Metadata.db.lease_id }}",
+	}
+	got.Generation++
+	require.NoError(t, r.Update(ctx, &got))
+
+	_, err = r.Reconcile(ctx, req)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"password":     "pass",
+		"password_b64": "cGFzcw==",
+		"username":     "user",
+		"metadata":     "app=1,db=db/creds/app/lease-2",
+	}, getBundleSecretData(t, r))
+
+	// the rendered value is unchanged when only the other source is re-read,
+	// the metadata of the source that is not due is taken from its status.
+	vc.renewErr = errors.New("lease expired")
+	setBundleSourceDue(t, r, "db")
+	_, err = r.Reconcile(ctx, req)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"password":     "pass",
+		"password_b64": "cGFzcw==",
+		"username":     "user",
+		"metadata":     "app=1,db=db/creds/app/lease-3",
+	}, getBundleSecretData(t, r))
+}
+
+// failingStatusClient is a client.Client whose status updates fail.
+type failingStatusClient struct {
+	client.Client
+}
+
+func (c *failingStatusClient) Status() client.StatusWriter {
+	return &failingStatusWriter{StatusWriter: c.Client.Status()}
+}
+
+type failingStatusWriter struct {
+	client.StatusWriter
+}
+
+func (w *failingStatusWriter) Update(context.Context, client.Object, ...client.SubResourceUpdateOption) error {
+	return errors.New("status update failed")
+}
+
+func TestVaultSecretBundleReconciler_Reconcile_statusUpdateError(t *testing.T) {
+	ctx := context.Background()
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "tenant-1", Name: "bundle"}}
+
+	vc := &bundleVaultClient{
+		dynamic: map[string]map[string]any{
+			"db/creds/app": {"username": "user"},
+		},
+	}
+	o := newTestBundle(secretsv1alpha1.VaultSecretBundleSource{
+		Name:  "db",
+		Type:  consts.BundleSourceTypeDynamic,
+		Mount: "db",
+		Path:  "creds/app",
+	})
+	r := newBundleReconciler(t, vc, o)
+	c := r.Client
+	r.Client = &failingStatusClient{Client: c}
+
+	// the lease that is not recorded in the status is revoked.
+	_, err := r.Reconcile(ctx, req)
+	assert.EqualError(t, err, "status update failed")
+	assert.Equal(t, []string{"db/creds/app/lease-1"}, vc.revoked)
+
+	r.Client = c
+	_, err = r.Reconcile(ctx, req)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"db/creds/app/lease-1"}, vc.revoked)
+
+	var got secretsv1alpha1.VaultSecretBundle
+	require.NoError(t, r.Get(ctx, req.NamespacedName, &got))
+	require.Len(t, got.Status.Sources, 1)
+	assert.Equal(t, "db/creds/app/lease-2", got.Status.Sources[0].SecretLease.ID)
+}
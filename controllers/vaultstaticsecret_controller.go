@@ -4,21 +4,32 @@
 package controllers
 
 import (
+	"bytes"
 	"context"
 	"encoding/base64"
+	"errors"
 	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/hashicorp/vault/api"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/json"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	secretsv1alpha1 "github.com/hashicorp/vault-secrets-operator/api/v1alpha1"
 	"github.com/hashicorp/vault-secrets-operator/internal/consts"
@@ -40,7 +51,8 @@ type VaultStaticSecretReconciler struct {
 //+kubebuilder:rbac:groups=secrets.hashicorp.com,resources=vaultstaticsecrets/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=secrets.hashicorp.com,resources=vaultstaticsecrets/finalizers,verbs=update
 //+kubebuilder:rbac:groups="",resources=events,verbs=create;patch
-//+kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create;update;patch
+//+kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch
+//+kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create;update;patch;delete
 //
 // required for rollout-restart
 //+kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch;patch
@@ -80,32 +92,58 @@ func (r *VaultStaticSecretReconciler) Reconcile(ctx context.Context, req ctrl.Re
 		requeueAfter = computeHorizonWithJitter(d)
 	}
 
-	var resp *api.KVSecret
-	switch o.Spec.Type {
-	case consts.KVSecretTypeV1:
-		w, err := c.KVv1(o.Spec.Mount)
-		if err != nil {
-			return ctrl.Result{}, err
-		}
-		resp, err = w.Get(ctx, o.Spec.Name)
-	case consts.KVSecretTypeV2:
-		w, err := c.KVv2(o.Spec.Mount)
-		if err != nil {
-			return ctrl.Result{}, err
-		}
-		resp, err = w.Get(ctx, o.Spec.Name)
-	default:
-		err = fmt.Errorf("unsupported secret type %q", o.Spec.Type)
+	if o.Spec.Type != consts.KVSecretTypeV2 && (o.Spec.Version != 0 || o.Spec.SyncCustomMetadata) {
+		err := fmt.Errorf("version and syncCustomMetadata are only supported for secret type %q",
+			consts.KVSecretTypeV2)
 		logger.Error(err, "")
 		r.Recorder.Event(o, corev1.EventTypeWarning, consts.ReasonVaultStaticSecret, err.Error())
 		return ctrl.Result{}, err
 	}
 
+	if o.Spec.SyncCustomMetadata && !o.Spec.Destination.Create {
+		// the annotations of a Secret that is not owned by VSO are never updated.
+		err := fmt.Errorf("syncCustomMetadata requires destination.create to be true")
+		logger.Error(err, "")
+		r.Recorder.Event(o, corev1.EventTypeWarning, consts.ReasonVaultStaticSecret, err.Error())
+		return ctrl.Result{}, err
+	}
+
+	c, err = resumeControlGroupRequest(ctx, c, o, &o.Status.ControlGroupAccessor)
 	if err != nil {
+		if handleControlGroupError(r.Recorder, o, &o.Status.ControlGroupAccessor, err) {
+			if err := r.Status().Update(ctx, o); err != nil {
+				return ctrl.Result{}, err
+			}
+			return ctrl.Result{RequeueAfter: controlGroupPollInterval}, nil
+		}
+		logger.Error(err, "Failed to read the control group request")
+		r.Recorder.Eventf(o, corev1.EventTypeWarning, consts.ReasonVaultClientError,
+			"Failed to read the control group request: %s", err)
+		return ctrl.Result{}, err
+	}
+
+	if o.Spec.Prefix != nil {
+		if o.Spec.Version != 0 || o.Spec.SyncCustomMetadata {
+			err := fmt.Errorf("version and syncCustomMetadata are not supported with prefix")
+			logger.Error(err, "")
+			r.Recorder.Event(o, corev1.EventTypeWarning, consts.ReasonVaultStaticSecret, err.Error())
+			return ctrl.Result{}, err
+		}
+		return r.syncPrefix(ctx, c, o, requeueAfter)
+	}
+
+	resp, err := vault.ReadKVSecret(ctx, c, o.Spec.Type, o.Spec.Mount, o.Spec.Name, o.Spec.Version)
+	if err != nil && !errors.Is(err, api.ErrSecretNotFound) {
+		if handleControlGroupError(r.Recorder, o, &o.Status.ControlGroupAccessor, err) {
+			if err := r.Status().Update(ctx, o); err != nil {
+				return ctrl.Result{}, err
+			}
+			return ctrl.Result{RequeueAfter: controlGroupPollInterval}, nil
+		}
 		logger.Error(err, "Failed to read Vault secret")
 		r.Recorder.Eventf(o, corev1.EventTypeWarning, consts.ReasonVaultClientError,
 			"Failed to read Vault secret: %s", err)
-		return ctrl.Result{}, nil
+		return ctrl.Result{}, err
 	}
 
 	if resp == nil {
@@ -117,7 +155,37 @@ func (r *VaultStaticSecretReconciler) Reconcile(ctx context.Context, req ctrl.Re
 		}, nil
 	}
 
+	if o.Spec.Type == consts.KVSecretTypeV2 {
+		o.Status.SecretVersion = makeSecretVersion(resp.VersionMetadata)
+		if resp.Data == nil && o.Status.SecretVersion != nil {
+			// the secret version was deleted or destroyed, so there is nothing to sync.
+			r.Recorder.Eventf(o, corev1.EventTypeWarning, consts.ReasonVaultStaticSecret,
+				"Vault secret version %d was deleted, mount %s, name %s",
+				o.Status.SecretVersion.Version, o.Spec.Mount, o.Spec.Name)
+			if err := r.Status().Update(ctx, o); err != nil {
+				return ctrl.Result{}, err
+			}
+			return ctrl.Result{
+				RequeueAfter: requeueAfter,
+			}, nil
+		}
+	}
+
+	var annotations map[string]string
+	if o.Spec.SyncCustomMetadata {
+		var invalid []string
+		annotations, invalid = makeCustomMetadataAnnotations(resp.CustomMetadata, o.Spec.Destination.Annotations)
+		if len(invalid) > 0 {
+			logger.Info("Skipping custom_metadata keys that are not valid annotation keys", "keys", invalid)
+			r.Recorder.Eventf(o, corev1.EventTypeWarning, consts.ReasonVaultStaticSecret,
+				"Skipping custom_metadata keys that are not valid annotation keys: %s", strings.Join(invalid, ", "))
+		}
+	}
+
 	data, err := makeK8sSecret(resp)
+	if err == nil {
+		data, err = helpers.TransformSecretData(o, data)
+	}
 	if err != nil {
 		logger.Error(err, "Failed to construct k8s secret")
 		r.Recorder.Eventf(o, corev1.EventTypeWarning, consts.ReasonVaultClientError,
@@ -125,6 +193,45 @@ func (r *VaultStaticSecretReconciler) Reconcile(ctx context.Context, req ctrl.Re
 		return ctrl.Result{}, err
 	}
 
+	data, err = r.renderTemplates(ctx, o, helpers.NewTemplateInputFromKVSecret(resp), data)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	return r.syncSecretData(ctx, o, data, annotations, requeueAfter)
+}
+
+// renderTemplates renders the Destination's templates, any error is reported by an event,
+// and by the resource's TemplatesRendered condition.
+func (r *VaultStaticSecretReconciler) renderTemplates(ctx context.Context, o *secretsv1alpha1.VaultStaticSecret,
+	input *helpers.TemplateInput, data map[string][]byte,
+) (map[string][]byte, error) {
+	data, err := helpers.RenderTemplates(ctx, r.Client, o, input, data)
+	helpers.SetTemplatesRenderedCondition(o, &o.Status.Conditions, err)
+	if err != nil {
+		log.FromContext(ctx).Error(err, "Failed to render templates")
+		r.Recorder.Eventf(o, corev1.EventTypeWarning, consts.ReasonTemplateError, "%s", err)
+		if err := r.Status().Update(ctx, o); err != nil {
+			return nil, err
+		}
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// syncSecretData syncs data to the Destination Secret, and updates the resource's status.
+// If annotations is not nil, then it replaces the Destination's annotations.
+func (r *VaultStaticSecretReconciler) syncSecretData(ctx context.Context, o *secretsv1alpha1.VaultStaticSecret,
+	data map[string][]byte, annotations map[string]string, requeueAfter time.Duration,
+) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	var syncOpts []helpers.SyncOption
+	if annotations != nil {
+		syncOpts = append(syncOpts, helpers.WithAnnotations(annotations))
+	}
+
 	var doRolloutRestart bool
 	syncSecret := true
 	if o.Spec.HMACSecretData {
@@ -152,8 +259,17 @@ func (r *VaultStaticSecretReconciler) Reconcile(ctx context.Context, req ctrl.Re
 			"targets", o.Spec.RolloutRestartTargets)
 	}
 
+	if !syncSecret && annotations != nil && o.Spec.Destination.Create {
+		// the custom_metadata is not part of the Secret's data,
+		// so any changes to it are not detected by the HMAC comparison.
+		if cur, ok, _ := helpers.GetSecret(ctx, r.Client, o); !ok || !stringMapsEqual(cur.Annotations, annotations) {
+			syncSecret = true
+			doRolloutRestart = false
+		}
+	}
+
 	if syncSecret {
-		if err := helpers.SyncSecret(ctx, r.Client, o, data); err != nil {
+		if err := helpers.SyncSecret(ctx, r.Client, o, data, syncOpts...); err != nil {
 			r.Recorder.Eventf(o, corev1.EventTypeWarning, consts.ReasonSecretSyncError,
 				"Failed to update k8s secret: %s", err)
 			return ctrl.Result{}, err
@@ -239,6 +355,362 @@ func (r *VaultStaticSecretReconciler) handleSecretHMAC(ctx context.Context, o *s
 	return macsEqual, newMAC, nil
 }
 
+// syncPrefix syncs all secrets found under the path prefix o.Spec.Name according to
+// o.Spec.Prefix.Mode. Any Secrets synced from secrets that no longer exist in Vault are deleted.
+func (r *VaultStaticSecretReconciler) syncPrefix(ctx context.Context, c vault.Client, o *secretsv1alpha1.VaultStaticSecret, requeueAfter time.Duration) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	if o.Spec.Prefix.Mode == consts.KVPrefixModePerLeaf && !o.Spec.Destination.Create {
+		err := fmt.Errorf("prefix mode %q requires destination create=true", consts.KVPrefixModePerLeaf)
+		logger.Error(err, "")
+		r.Recorder.Event(o, corev1.EventTypeWarning, consts.ReasonVaultStaticSecret, err.Error())
+		return ctrl.Result{}, err
+	}
+
+	secrets, err := getKVPrefixSecrets(ctx, c, o)
+	if err != nil {
+		if handleControlGroupError(r.Recorder, o, &o.Status.ControlGroupAccessor, err) {
+			if err := r.Status().Update(ctx, o); err != nil {
+				return ctrl.Result{}, err
+			}
+			return ctrl.Result{RequeueAfter: controlGroupPollInterval}, nil
+		}
+		logger.Error(err, "Failed to read Vault secrets")
+		r.Recorder.Eventf(o, corev1.EventTypeWarning, consts.ReasonVaultClientError,
+			"Failed to read Vault secrets under prefix %s: %s", o.Spec.Name, err)
+		return ctrl.Result{}, err
+	}
+
+	var result ctrl.Result
+	var keep []string
+	switch o.Spec.Prefix.Mode {
+	case consts.KVPrefixModePerLeaf:
+		result, keep, err = r.syncLeafSecrets(ctx, o, secrets, requeueAfter)
+	default:
+		var merged, data map[string][]byte
+		merged, err = makeMergedK8sSecret(secrets)
+		if err == nil {
+			data, err = helpers.TransformSecretData(o, merged)
+		}
+		if err != nil {
+			logger.Error(err, "Failed to construct k8s secret")
+			r.Recorder.Eventf(o, corev1.EventTypeWarning, consts.ReasonVaultClientError,
+				"Failed to construct k8s secret: %s", err)
+			return ctrl.Result{}, err
+		}
+		data, err = r.renderTemplates(ctx, o, helpers.NewTemplateInputFromData(merged), data)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		result, err = r.syncSecretData(ctx, o, data, nil, requeueAfter)
+		keep = []string{o.Spec.Destination.Name}
+	}
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if err := helpers.PruneSecrets(ctx, r.Client, o, keep...); err != nil {
+		r.Recorder.Eventf(o, corev1.EventTypeWarning, consts.ReasonSecretSyncError,
+			"Failed to delete stale k8s secrets: %s", err)
+		return ctrl.Result{}, err
+	}
+
+	return result, nil
+}
+
+// syncLeafSecrets syncs each of the secrets to its own Secret, returning the names of all the Secrets.
+func (r *VaultStaticSecretReconciler) syncLeafSecrets(ctx context.Context, o *secretsv1alpha1.VaultStaticSecret,
+	secrets map[string]*api.KVSecret, requeueAfter time.Duration,
+) (ctrl.Result, []string, error) {
+	dataByName := make(map[string]map[string][]byte, len(secrets))
+	for leaf, secret := range secrets {
+		name, err := makeLeafSecretName(o.Spec.Destination.Name, leaf)
+		if err != nil {
+			r.Recorder.Event(o, corev1.EventTypeWarning, consts.ReasonInvalidConfiguration, err.Error())
+			return ctrl.Result{}, nil, err
+		}
+		if _, ok := dataByName[name]; ok {
+			err := fmt.Errorf("more than one Vault secret maps to the k8s secret %s", name)
+			r.Recorder.Event(o, corev1.EventTypeWarning, consts.ReasonVaultStaticSecret, err.Error())
+			return ctrl.Result{}, nil, err
+		}
+
+		data, err := makeK8sSecret(secret)
+		if err == nil {
+			data, err = helpers.TransformSecretData(o, data)
+		}
+		if err != nil {
+			r.Recorder.Eventf(o, corev1.EventTypeWarning, consts.ReasonVaultClientError,
+				"Failed to construct k8s secret %s: %s", name, err)
+			return ctrl.Result{}, nil, err
+		}
+		data, err = r.renderTemplates(ctx, o, helpers.NewTemplateInputFromKVSecret(secret), data)
+		if err != nil {
+			return ctrl.Result{}, nil, err
+		}
+		dataByName[name] = data
+	}
+
+	names := make([]string, 0, len(dataByName))
+	for name := range dataByName {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var doRolloutRestart bool
+	if o.Spec.HMACSecretData {
+		if requeueAfter == 0 {
+			requeueAfter = computeHorizonWithJitter(time.Second * 60)
+		}
+
+		// the MAC covers all the Secrets' data, it is only used for detecting
+		// changes in Vault, drift is detected by comparing each Secret's data below.
+		message, err := json.Marshal(dataByName)
+		if err != nil {
+			return ctrl.Result{}, nil, err
+		}
+		newMAC, err := r.HMACFunc(ctx, r.Client, message)
+		if err != nil {
+			return ctrl.Result{}, nil, err
+		}
+		if o.Status.SecretMAC != "" {
+			lastMAC, err := base64.StdEncoding.DecodeString(o.Status.SecretMAC)
+			if err != nil {
+				return ctrl.Result{}, nil, err
+			}
+			doRolloutRestart = !vault.EqualMACS(lastMAC, newMAC)
+		}
+		o.Status.SecretMAC = base64.StdEncoding.EncodeToString(newMAC)
+	}
+
+	var synced int
+	for _, name := range names {
+		data := dataByName[name]
+		var cur corev1.Secret
+		err := r.Client.Get(ctx, client.ObjectKey{Namespace: o.Namespace, Name: name}, &cur)
+		if err == nil && secretDataEqual(cur.Data, data) {
+			continue
+		}
+		if err != nil && !apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil, err
+		}
+
+		if err := helpers.SyncSecret(ctx, r.Client, o, data, helpers.WithName(name)); err != nil {
+			r.Recorder.Eventf(o, corev1.EventTypeWarning, consts.ReasonSecretSyncError,
+				"Failed to update k8s secret %s: %s", name, err)
+			return ctrl.Result{}, nil, err
+		}
+		synced++
+	}
+
+	if synced > 0 {
+		reason := consts.ReasonSecretSynced
+		if doRolloutRestart {
+			reason = consts.ReasonSecretRotated
+			// rollout-restart errors are not retryable
+			// all error reporting is handled by helpers.HandleRolloutRestarts
+			_ = helpers.HandleRolloutRestarts(ctx, r.Client, o, r.Recorder)
+		}
+		r.Recorder.Eventf(o, corev1.EventTypeNormal, reason, "Synced %d of %d secrets", synced, len(names))
+	} else {
+		r.Recorder.Event(o, corev1.EventTypeNormal, consts.ReasonSecretSync, "Secret sync not required")
+	}
+
+	if err := r.Status().Update(ctx, o); err != nil {
+		return ctrl.Result{}, nil, err
+	}
+
+	return ctrl.Result{
+		RequeueAfter: requeueAfter,
+	}, names, nil
+}
+
+// getKVPrefixSecrets returns all secrets found under the path prefix o.Spec.Name,
+// keyed by their path relative to the prefix.
+func getKVPrefixSecrets(ctx context.Context, c vault.Client, o *secretsv1alpha1.VaultStaticSecret) (map[string]*api.KVSecret, error) {
+	prefix := strings.Trim(o.Spec.Name, "/")
+	leaves, err := listKVPrefix(ctx, c, o.Spec.Type, o.Spec.Mount, prefix, o.Spec.Prefix.Depth)
+	if err != nil {
+		return nil, err
+	}
+
+	secrets := make(map[string]*api.KVSecret, len(leaves))
+	for _, leaf := range leaves {
+		resp, err := vault.ReadKVSecret(ctx, c, o.Spec.Type, o.Spec.Mount, path.Join(prefix, leaf), 0)
+		if err != nil {
+			if errors.Is(err, api.ErrSecretNotFound) {
+				// the secret was removed after the listing.
+				continue
+			}
+			return nil, err
+		}
+		// the latest version of a kv-v2 secret was deleted.
+		if resp == nil || resp.Data == nil {
+			continue
+		}
+		secrets[leaf] = resp
+	}
+
+	return secrets, nil
+}
+
+// listKVPrefix recursively lists the path prefix, returning the paths of all secrets relative
+// to the prefix. At most depth levels are listed, a depth of 1 only includes the secrets directly
+// under the prefix.
+func listKVPrefix(ctx context.Context, c vault.Client, kvType, mount, prefix string, depth int) ([]string, error) {
+	var leaves []string
+	var walk func(dir string, depth int) error
+	walk = func(dir string, depth int) error {
+		var listPath string
+		switch kvType {
+		case consts.KVSecretTypeV1:
+			listPath = path.Join(mount, prefix, dir)
+		case consts.KVSecretTypeV2:
+			listPath = path.Join(mount, "metadata", prefix, dir)
+		default:
+			return fmt.Errorf("unsupported secret type %q", kvType)
+		}
+
+		resp, err := c.List(ctx, listPath)
+		if err != nil {
+			return err
+		}
+		if resp == nil {
+			return nil
+		}
+
+		keys, _ := resp.Data["keys"].([]any)
+		for _, k := range keys {
+			key, ok := k.(string)
+			if !ok || key == "" {
+				continue
+			}
+			if strings.HasSuffix(key, "/") {
+				if depth > 1 {
+					if err := walk(dir+key, depth-1); err != nil {
+						return err
+					}
+				}
+				continue
+			}
+			leaves = append(leaves, dir+key)
+		}
+		return nil
+	}
+
+	if depth < 1 {
+		depth = 1
+	}
+	if err := walk("", depth); err != nil {
+		return nil, err
+	}
+
+	sort.Strings(leaves)
+	return leaves, nil
+}
+
+// makeMergedK8sSecret returns the k8s secret data for all secrets, keyed by their path relative
+// to the prefix. Each key is prefixed with the secret's path, with "/" replaced by "_".
+func makeMergedK8sSecret(secrets map[string]*api.KVSecret) (map[string][]byte, error) {
+	data := make(map[string][]byte)
+	for leaf, secret := range secrets {
+		d, err := makeK8sSecret(secret)
+		if err != nil {
+			return nil, fmt.Errorf("secret %s: %w", leaf, err)
+		}
+
+		keyPrefix := strings.ReplaceAll(leaf, "/", "_") + "_"
+		for k, v := range d {
+			// the raw secret is only meaningful for a single secret.
+			if k == "_raw" {
+				continue
+			}
+			key := keyPrefix + k
+			if _, ok := data[key]; ok {
+				return nil, fmt.Errorf("duplicate key %q in merged secret", key)
+			}
+			data[key] = v
+		}
+	}
+	return data, nil
+}
+
+var invalidSecretNameChars = regexp.MustCompile(`[^a-z0-9.-]+`)
+
+// makeLeafSecretName returns the name of the k8s secret for the secret at leaf.
+// An error is returned if the name is not a valid DNS subdomain, e.g. it is too long.
+func makeLeafSecretName(name, leaf string) (string, error) {
+	suffix := invalidSecretNameChars.ReplaceAllString(strings.ToLower(leaf), "-")
+	result := name + "-" + strings.Trim(suffix, "-.")
+	if errs := validation.IsDNS1123Subdomain(result); len(errs) > 0 {
+		return "", fmt.Errorf("invalid k8s secret name %q for the Vault secret %s: %s",
+			result, leaf, strings.Join(errs, ", "))
+	}
+	return result, nil
+}
+
+func secretDataEqual(a, b map[string][]byte) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, v := range a {
+		if o, ok := b[k]; !ok || !bytes.Equal(o, v) {
+			return false
+		}
+	}
+	return true
+}
+
+// makeSecretVersion returns the VaultSecretVersion for the kv-v2 secret's metadata.
+func makeSecretVersion(metadata *api.KVVersionMetadata) *secretsv1alpha1.VaultSecretVersion {
+	if metadata == nil {
+		return nil
+	}
+
+	v := &secretsv1alpha1.VaultSecretVersion{
+		Version:     metadata.Version,
+		CreatedTime: metav1.NewTime(metadata.CreatedTime),
+		Destroyed:   metadata.Destroyed,
+	}
+	if !metadata.DeletionTime.IsZero() {
+		t := metav1.NewTime(metadata.DeletionTime)
+		v.DeletionTime = &t
+	}
+	return v
+}
+
+// makeCustomMetadataAnnotations returns the Secret annotations for the kv-v2 secret's
+// custom_metadata, any annotations in overrides take precedence. The custom_metadata keys
+// that are not valid annotation keys are skipped, and returned in sorted order.
+func makeCustomMetadataAnnotations(customMetadata map[string]any, overrides map[string]string) (map[string]string, []string) {
+	annotations := make(map[string]string, len(customMetadata)+len(overrides))
+	var invalid []string
+	for k, v := range customMetadata {
+		if errs := validation.IsQualifiedName(k); len(errs) > 0 {
+			invalid = append(invalid, k)
+			continue
+		}
+		annotations[k] = fmt.Sprint(v)
+	}
+	for k, v := range overrides {
+		annotations[k] = v
+	}
+	sort.Strings(invalid)
+	return annotations, invalid
+}
+
+func stringMapsEqual(a, b map[string]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, v := range a {
+		if o, ok := b[k]; !ok || o != v {
+			return false
+		}
+	}
+	return true
+}
+
 // SetupWithManager sets up the controller with the Manager.
 func makeK8sSecret(vaultSecret *api.KVSecret) (map[string][]byte, error) {
 	if vaultSecret.Raw == nil {
@@ -273,7 +745,11 @@ func makeK8sSecret(vaultSecret *api.KVSecret) (map[string][]byte, error) {
 
 func (r *VaultStaticSecretReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&secretsv1alpha1.VaultStaticSecret{}).
-		WithEventFilter(predicate.GenerationChangedPredicate{}).
+		For(&secretsv1alpha1.VaultStaticSecret{},
+			builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		// the templates are rendered on every sync.
+		Watches(&source.Kind{Type: &corev1.ConfigMap{}},
+			handler.EnqueueRequestsFromMapFunc(mapTemplatesConfigMap(r.Client, &secretsv1alpha1.VaultStaticSecretList{})),
+			builder.WithPredicates(predicate.ResourceVersionChangedPredicate{})).
 		Complete(r)
 }
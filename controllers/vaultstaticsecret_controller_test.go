@@ -4,11 +4,21 @@
 package controllers
 
 import (
+	"context"
 	"fmt"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/hashicorp/vault/api"
 	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	secretsv1alpha1 "github.com/hashicorp/vault-secrets-operator/api/v1alpha1"
+	"github.com/hashicorp/vault-secrets-operator/internal/consts"
+	"github.com/hashicorp/vault-secrets-operator/internal/vault"
 )
 
 func Test_makeK8sSecret(t *testing.T) {
@@ -119,3 +129,336 @@ func Test_makeK8sSecret(t *testing.T) {
 		})
 	}
 }
+
+func Test_makeSecretVersion(t *testing.T) {
+	created := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	deleted := created.Add(time.Hour)
+	deletedTime := metav1.NewTime(deleted)
+
+	tests := map[string]struct {
+		metadata *api.KVVersionMetadata
+		expected *secretsv1alpha1.VaultSecretVersion
+	}{
+		"nil": {},
+		"current": {
+			metadata: &api.KVVersionMetadata{
+				Version:     2,
+				CreatedTime: created,
+			},
+			expected: &secretsv1alpha1.VaultSecretVersion{
+				Version:     2,
+				CreatedTime: metav1.NewTime(created),
+			},
+		},
+		"deleted": {
+			metadata: &api.KVVersionMetadata{
+				Version:      1,
+				CreatedTime:  created,
+				DeletionTime: deleted,
+			},
+			expected: &secretsv1alpha1.VaultSecretVersion{
+				Version:      1,
+				CreatedTime:  metav1.NewTime(created),
+				DeletionTime: &deletedTime,
+			},
+		},
+		"destroyed": {
+			metadata: &api.KVVersionMetadata{
+				Version:     1,
+				CreatedTime: created,
+				Destroyed:   true,
+			},
+			expected: &secretsv1alpha1.VaultSecretVersion{
+				Version:     1,
+				CreatedTime: metav1.NewTime(created),
+				Destroyed:   true,
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, makeSecretVersion(tc.metadata))
+		})
+	}
+}
+
+func Test_makeCustomMetadataAnnotations(t *testing.T) {
+	tests := map[string]struct {
+		customMetadata map[string]any
+		overrides      map[string]string
+		expected       map[string]string
+		invalid        []string
+	}{
+		"empty": {
+			expected: map[string]string{},
+		},
+		"custom-metadata": {
+			customMetadata: map[string]any{
+				"owner": "team-1",
+				"tier":  "gold",
+			},
+			expected: map[string]string{
+				"owner": "team-1",
+				"tier":  "gold",
+			},
+		},
+		"with-overrides": {
+			customMetadata: map[string]any{
+				"owner": "team-1",
+				"tier":  "gold",
+			},
+			overrides: map[string]string{
+				"owner": "team-2",
+				"foo":   "bar",
+			},
+			expected: map[string]string{
+				"owner": "team-2",
+				"tier":  "gold",
+				"foo":   "bar",
+			},
+		},
+		"invalid-keys": {
+			customMetadata: map[string]any{
+				"owner":              "team-1",
+				"example.com/tier":   "gold",
+				"cost center":        "1234",
+				"-owner":             "team-2",
+				"a/b/c":              "d",
+				"example.com/":       "e",
+				"Example.com/review": "true",
+				"example.com/Review": "true",
+			},
+			overrides: map[string]string{
+				"foo": "bar",
+			},
+			expected: map[string]string{
+				"owner":              "team-1",
+				"example.com/tier":   "gold",
+				"example.com/Review": "true",
+				"foo":                "bar",
+			},
+			invalid: []string{"-owner", "Example.com/review", "a/b/c", "cost center", "example.com/"},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			annotations, invalid := makeCustomMetadataAnnotations(tc.customMetadata, tc.overrides)
+			assert.Equal(t, tc.expected, annotations)
+			assert.Equal(t, tc.invalid, invalid)
+		})
+	}
+}
+
+// listClient is a vault.Client that only supports List, keys maps a path to its listed keys.
+type listClient struct {
+	vault.Client
+	keys map[string][]any
+}
+
+func (c *listClient) List(_ context.Context, path string) (*api.Secret, error) {
+	keys, ok := c.keys[path]
+	if !ok {
+		return nil, nil
+	}
+	return &api.Secret{Data: map[string]any{"keys": keys}}, nil
+}
+
+func Test_listKVPrefix(t *testing.T) {
+	c := &listClient{
+		keys: map[string][]any{
+			"kv/metadata/app/team":           {"api", "db/"},
+			"kv/metadata/app/team/db":        {"admin", "config/"},
+			"kv/metadata/app/team/db/config": {"replica"},
+			"kv1/app/team":                   {"api", "db/"},
+			"kv1/app/team/db":                {"admin"},
+		},
+	}
+
+	tests := map[string]struct {
+		kvType        string
+		mount         string
+		depth         int
+		expected      []string
+		expectedError error
+	}{
+		"kv-v2-depth-1": {
+			kvType:   consts.KVSecretTypeV2,
+			mount:    "kv",
+			depth:    1,
+			expected: []string{"api"},
+		},
+		"kv-v2-depth-2": {
+			kvType:   consts.KVSecretTypeV2,
+			mount:    "kv",
+			depth:    2,
+			expected: []string{"api", "db/admin"},
+		},
+		"kv-v2-depth-3": {
+			kvType:   consts.KVSecretTypeV2,
+			mount:    "kv",
+			depth:    3,
+			expected: []string{"api", "db/admin", "db/config/replica"},
+		},
+		"kv-v1": {
+			kvType:   consts.KVSecretTypeV1,
+			mount:    "kv1",
+			depth:    2,
+			expected: []string{"api", "db/admin"},
+		},
+		"not-found": {
+			kvType: consts.KVSecretTypeV2,
+			mount:  "other",
+			depth:  1,
+		},
+		"unsupported-type": {
+			kvType:        "kv-v3",
+			mount:         "kv",
+			depth:         1,
+			expectedError: fmt.Errorf(`unsupported secret type "kv-v3"`),
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := listKVPrefix(context.Background(), c, tc.kvType, tc.mount, "app/team", tc.depth)
+			if tc.expectedError != nil {
+				assert.EqualError(t, err, tc.expectedError.Error())
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, got)
+		})
+	}
+}
+
+func Test_makeMergedK8sSecret(t *testing.T) {
+	tests := map[string]struct {
+		secrets       map[string]*api.KVSecret
+		expected      map[string][]byte
+		expectedError error
+	}{
+		"merged": {
+			secrets: map[string]*api.KVSecret{
+				"api": {
+					Data: map[string]any{"token": "foo"},
+					Raw:  &api.Secret{Data: map[string]any{"token": "foo"}},
+				},
+				"db/admin": {
+					Data: map[string]any{"password": "bar", "port": 5432},
+					Raw:  &api.Secret{Data: map[string]any{"password": "bar", "port": 5432}},
+				},
+			},
+			expected: map[string][]byte{
+				"api_token":         []byte("foo"),
+				"db_admin_password": []byte("bar"),
+				"db_admin_port":     []byte("5432"),
+			},
+		},
+		"duplicate-key": {
+			secrets: map[string]*api.KVSecret{
+				"db/admin": {
+					Data: map[string]any{"password": "foo"},
+					Raw:  &api.Secret{Data: map[string]any{"password": "foo"}},
+				},
+				"db": {
+					Data: map[string]any{"admin_password": "bar"},
+					Raw:  &api.Secret{Data: map[string]any{"admin_password": "bar"}},
+				},
+			},
+			expectedError: fmt.Errorf(`duplicate key "db_admin_password" in merged secret`),
+		},
+		"invalid-secret": {
+			secrets: map[string]*api.KVSecret{
+				"api": {
+					Data: map[string]any{"token": "foo"},
+				},
+			},
+			expectedError: fmt.Errorf("secret api: raw portion of vault secret was nil"),
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := makeMergedK8sSecret(tc.secrets)
+			if tc.expectedError != nil {
+				assert.EqualError(t, err, tc.expectedError.Error())
+				assert.Nil(t, got)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expected, got)
+			}
+		})
+	}
+}
+
+func Test_makeLeafSecretName(t *testing.T) {
+	tests := map[string]struct {
+		leaf          string
+		expected      string
+		expectedError string
+	}{
+		"simple": {
+			leaf:     "api",
+			expected: "app-api",
+		},
+		"nested": {
+			leaf:     "db/admin",
+			expected: "app-db-admin",
+		},
+		"invalid-chars": {
+			leaf:     "DB/Admin_User",
+			expected: "app-db-admin-user",
+		},
+		"trailing-invalid-chars": {
+			leaf:     "db/_admin_",
+			expected: "app-db-admin",
+		},
+		"too-long": {
+			leaf: strings.Repeat("a", 250),
+			expectedError: fmt.Sprintf(
+				"invalid k8s secret name %q for the Vault secret %s: must be no more than 253 characters",
+				"app-"+strings.Repeat("a", 250), strings.Repeat("a", 250)),
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := makeLeafSecretName("app", tc.leaf)
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError)
+				assert.Empty(t, got)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestVaultStaticSecretReconciler_syncLeafSecrets_invalidName(t *testing.T) {
+	recorder := record.NewFakeRecorder(1)
+	r := &VaultStaticSecretReconciler{
+		Recorder: recorder,
+	}
+	o := &secretsv1alpha1.VaultStaticSecret{
+		Spec: secretsv1alpha1.VaultStaticSecretSpec{
+			Destination: secretsv1alpha1.Destination{
+				Name:   "app",
+				Create: true,
+			},
+		},
+	}
+	leaf := strings.Repeat("a", 250)
+	secrets := map[string]*api.KVSecret{
+		leaf: {Data: map[string]any{"foo": "bar"}},
+	}
+
+	_, _, err := r.syncLeafSecrets(context.Background(), o, secrets, 0)
+	assert.ErrorContains(t, err, "must be no more than 253 characters")
+	if assert.Len(t, recorder.Events, 1) {
+		assert.Equal(t, fmt.Sprintf("%s %s %s", corev1.EventTypeWarning, consts.ReasonInvalidConfiguration, err),
+			<-recorder.Events)
+	}
+}
@@ -57,6 +57,12 @@ func GetVaultAuthAndTarget(ctx context.Context, c client.Client, obj client.Obje
 			Namespace: o.Namespace,
 			Name:      o.Name,
 		}
+	case *secretsv1alpha1.VaultSecretBundle:
+		authRef = o.Spec.VaultAuthRef
+		target = types.NamespacedName{
+			Namespace: o.Namespace,
+			Name:      o.Name,
+		}
 	default:
 		return nil, types.NamespacedName{}, fmt.Errorf("unsupported type %T", o)
 	}
@@ -230,7 +236,7 @@ func FindVaultAuthForStorageEncryption(ctx context.Context, c client.Client) (*s
 
 // GetVaultNamespace for the Syncable Secret type object.
 //
-// Supported types for obj are: VaultDynamicSecret, VaultStaticSecret. VaultPKISecret
+// Supported types for obj are: VaultDynamicSecret, VaultStaticSecret, VaultPKISecret, VaultSecretBundle
 func GetVaultNamespace(obj client.Object) (string, error) {
 	var ns string
 	switch o := obj.(type) {
@@ -240,6 +246,8 @@ func GetVaultNamespace(obj client.Object) (string, error) {
 		ns = o.Spec.Namespace
 	case *secretsv1alpha1.VaultDynamicSecret:
 		ns = o.Spec.Namespace
+	case *secretsv1alpha1.VaultSecretBundle:
+		ns = o.Spec.Namespace
 	default:
 		return "", fmt.Errorf("unsupported type %T", o)
 	}
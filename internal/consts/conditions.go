@@ -0,0 +1,12 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package consts
+
+const (
+	TypeDegraded          = "Degraded"
+	TypeHealthy           = "Healthy"
+	TypeSealed            = "Sealed"
+	TypeStandby           = "Standby"
+	TypeTemplatesRendered = "TemplatesRendered"
+)
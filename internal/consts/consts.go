@@ -8,4 +8,14 @@ const (
 
 	KVSecretTypeV2 = "kv-v2"
 	KVSecretTypeV1 = "kv-v1"
+
+	KVPrefixModeMerged  = "merged"
+	KVPrefixModePerLeaf = "perLeaf"
+
+	BundleSourceTypeDynamic = "dynamic"
+	BundleSourceTypePKI     = "pki"
+
+	ConflictPolicyError     = "error"
+	ConflictPolicyFirstWins = "firstWins"
+	ConflictPolicyLastWins  = "lastWins"
 )
@@ -5,9 +5,16 @@ package consts
 
 const (
 	ReasonAccepted                = "Accepted"
+	ReasonCACertRotated           = "CACertRotated"
+	ReasonClientCertRotated       = "ClientCertRotated"
+	ReasonConnectionHealthy       = "ConnectionHealthy"
+	ReasonConnectionUnhealthy     = "ConnectionUnhealthy"
+	ReasonControlGroupPending     = "ControlGroupPending"
+	ReasonHeadersSecretRotated    = "HeadersSecretRotated"
 	ReasonInvalidConfiguration    = "InvalidConfiguration"
 	ReasonInvalidResourceRef      = "InvalidResourceRef"
 	ReasonK8sClientError          = "K8sClientError"
+	ReasonProxySecretRotated      = "ProxySecretRotated"
 	ReasonRolloutRestartFailed    = "RolloutRestartFailed"
 	ReasonRolloutRestartTriggered = "RolloutRestartTriggered"
 	ReasonSecretLeaseRenewal      = "SecretLeaseRenewal"
@@ -18,8 +25,17 @@ const (
 	ReasonSecretSyncError         = "SecretSyncError"
 	ReasonSecretSynced            = "SecretSynced"
 	ReasonStatusUpdateError       = "StatusUpdateError"
+	ReasonTemplateError           = "TemplateError"
+	ReasonTemplatesRendered       = "TemplatesRendered"
 	ReasonUnrecoverable           = "Unrecoverable"
+	ReasonVaultActive             = "VaultActive"
 	ReasonVaultClientConfigError  = "VaultClientConfigError"
 	ReasonVaultClientError        = "VaultClientError"
+	ReasonVaultHealthy            = "VaultHealthy"
+	ReasonVaultSealed             = "VaultSealed"
+	ReasonVaultStandby            = "VaultStandby"
 	ReasonVaultStaticSecret       = "VaultStaticSecretError"
+	ReasonVaultUninitialized      = "VaultUninitialized"
+	ReasonVaultUnreachable        = "VaultUnreachable"
+	ReasonVaultUnsealed           = "VaultUnsealed"
 )
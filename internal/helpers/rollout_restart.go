@@ -24,7 +24,8 @@ import (
 const AnnotationRestartedAt = "vso.secrets.hashicorp.com/restartedAt"
 
 // HandleRolloutRestarts for all v1alpha1.RolloutRestartTarget(s) configured for obj.
-// Supported objs are: v1alpha1.VaultDynamicSecret, v1alpha1.VaultStaticSecret, v1alpha1.VaultPKISecret
+// Supported objs are: v1alpha1.VaultDynamicSecret, v1alpha1.VaultStaticSecret, v1alpha1.VaultPKISecret,
+// v1alpha1.VaultSecretBundle
 // Please note the following:
 // - a rollout-restart will be triggered for each configured v1alpha1.RolloutRestartTarget
 // - the rollout-restart action has no support for roll-back
@@ -42,6 +43,8 @@ func HandleRolloutRestarts(ctx context.Context, client ctrlclient.Client, obj ct
 		targets = t.Spec.RolloutRestartTargets
 	case *v1alpha1.VaultPKISecret:
 		targets = t.Spec.RolloutRestartTargets
+	case *v1alpha1.VaultSecretBundle:
+		targets = t.Spec.RolloutRestartTargets
 	default:
 		return fmt.Errorf("unsupported type %T", t)
 	}
@@ -46,7 +46,7 @@ type SyncableSecretMetaData struct {
 // NewSyncableSecretMetaData returns SyncableSecretMetaData if obj is a supported type.
 // An error will be returned of obj is not a supported type.
 //
-// Supported types for obj are: VaultDynamicSecret, VaultStaticSecret. VaultPKISecret
+// Supported types for obj are: VaultDynamicSecret, VaultStaticSecret, VaultPKISecret, VaultSecretBundle
 func NewSyncableSecretMetaData(obj ctrlclient.Object) (*SyncableSecretMetaData, error) {
 	switch t := obj.(type) {
 	case *secretsv1alpha1.VaultDynamicSecret:
@@ -67,26 +67,68 @@ func NewSyncableSecretMetaData(obj ctrlclient.Object) (*SyncableSecretMetaData,
 			APIVersion:  t.APIVersion,
 			Kind:        t.Kind,
 		}, nil
+	case *secretsv1alpha1.VaultSecretBundle:
+		return &SyncableSecretMetaData{
+			Destination: &t.Spec.Destination,
+			APIVersion:  t.APIVersion,
+			Kind:        t.Kind,
+		}, nil
 	default:
 		return nil, fmt.Errorf("unsupported type %T", t)
 	}
 }
 
+// SyncOption configures optional behaviour of SyncSecret.
+type SyncOption func(*syncOptions)
+
+type syncOptions struct {
+	name        string
+	annotations map[string]string
+}
+
+// WithName sets the name of the Secret to sync, overriding the object's
+// Spec.Destination.Name. Only supported if the Destination is configured with Create.
+func WithName(name string) SyncOption {
+	return func(o *syncOptions) {
+		o.name = name
+	}
+}
+
+// WithAnnotations sets the annotations to apply to the Secret, overriding those
+// configured in the object's Spec.Destination.Annotations.
+func WithAnnotations(annotations map[string]string) SyncOption {
+	return func(o *syncOptions) {
+		o.annotations = annotations
+	}
+}
+
 // SyncSecret writes data to a Kubernetes Secret for obj. All configuring is derived from the object's
 // Spec.Destination configuration.
 //
 // See NewSyncableSecretMetaData for the supported types for obj.
-func SyncSecret(ctx context.Context, client ctrlclient.Client, obj ctrlclient.Object, data map[string][]byte) error {
+func SyncSecret(ctx context.Context, client ctrlclient.Client, obj ctrlclient.Object, data map[string][]byte, opts ...SyncOption) error {
 	meta, err := NewSyncableSecretMetaData(obj)
 	if err != nil {
 		return err
 	}
 
+	options := &syncOptions{
+		name:        meta.Destination.Name,
+		annotations: meta.Destination.Annotations,
+	}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	if options.name != meta.Destination.Name && !meta.Destination.Create {
+		return fmt.Errorf("syncing to secret %s requires create=true", options.name)
+	}
+
 	logger := log.FromContext(ctx).WithName("syncSecret").WithValues(
-		"secretName", meta.Destination.Name, "create", meta.Destination.Create)
+		"secretName", options.name, "create", meta.Destination.Create)
 	key := ctrlclient.ObjectKey{
 		Namespace: obj.GetNamespace(),
-		Name:      meta.Destination.Name,
+		Name:      options.name,
 	}
 
 	var dest corev1.Secret
@@ -121,16 +163,7 @@ func SyncSecret(ctx context.Context, client ctrlclient.Client, obj ctrlclient.Ob
 		secretType = meta.Destination.Type
 	}
 
-	// these are the OwnerReferences that should be included in any Secret that is created/owned by
-	// the syncable-secret
-	references := []metav1.OwnerReference{
-		{
-			APIVersion: meta.APIVersion,
-			Kind:       meta.Kind,
-			Name:       obj.GetName(),
-			UID:        obj.GetUID(),
-		},
-	}
+	references := ownerReferences(meta, obj)
 	if exists {
 		logger.V(consts.LogLevelDebug).Info("Found pre-existing secret",
 			"secret", ctrlclient.ObjectKeyFromObject(&dest))
@@ -142,7 +175,7 @@ func SyncSecret(ctx context.Context, client ctrlclient.Client, obj ctrlclient.Ob
 		// secret does not exist, so we are going to create it.
 		dest = corev1.Secret{
 			ObjectMeta: metav1.ObjectMeta{
-				Name:      meta.Destination.Name,
+				Name:      options.name,
 				Namespace: obj.GetNamespace(),
 			},
 		}
@@ -163,7 +196,7 @@ func SyncSecret(ctx context.Context, client ctrlclient.Client, obj ctrlclient.Ob
 	// add any annotations configured in meta.Destination.Labels
 	dest.Data = data
 	dest.Type = secretType
-	dest.SetAnnotations(meta.Destination.Annotations)
+	dest.SetAnnotations(options.annotations)
 	dest.SetLabels(labels)
 	dest.SetOwnerReferences(references)
 
@@ -176,6 +209,45 @@ func SyncSecret(ctx context.Context, client ctrlclient.Client, obj ctrlclient.Ob
 	return client.Create(ctx, &dest)
 }
 
+// PruneSecrets deletes all Secrets owned by obj, except for those named in keep.
+// It is used to garbage collect the Secrets that obj no longer syncs to.
+//
+// See NewSyncableSecretMetaData for the supported types for obj.
+func PruneSecrets(ctx context.Context, client ctrlclient.Client, obj ctrlclient.Object, keep ...string) error {
+	meta, err := NewSyncableSecretMetaData(obj)
+	if err != nil {
+		return err
+	}
+
+	logger := log.FromContext(ctx).WithName("pruneSecrets")
+	var secrets corev1.SecretList
+	if err := client.List(ctx, &secrets, ctrlclient.InNamespace(obj.GetNamespace()),
+		ctrlclient.MatchingLabels(OwnerLabels)); err != nil {
+		return err
+	}
+
+	skip := make(map[string]bool, len(keep))
+	for _, name := range keep {
+		skip[name] = true
+	}
+
+	var errs error
+	references := ownerReferences(meta, obj)
+	for _, s := range secrets.Items {
+		// only ever delete Secrets that explicitly reference obj as their owner.
+		if skip[s.Name] || len(s.OwnerReferences) == 0 || checkSecretIsOwnedByObj(&s, references) != nil {
+			continue
+		}
+
+		logger.V(consts.LogLevelDebug).Info("Deleting secret", "secret", ctrlclient.ObjectKeyFromObject(&s))
+		if err := client.Delete(ctx, &s); err != nil && !apierrors.IsNotFound(err) {
+			errs = errors.Join(errs, err)
+		}
+	}
+
+	return errs
+}
+
 // CheckSecretExists checks if the Secret configured on obj exists.
 // Returns true if the secret exists, false if the secret was not found.
 // If any error, other than apierrors.IsNotFound, is encountered,
@@ -215,6 +287,19 @@ func getSecretExists(ctx context.Context, client ctrlclient.Client, obj ctrlclie
 	return &s, true, nil
 }
 
+// ownerReferences returns the OwnerReferences that should be included in any Secret that is
+// created/owned by the syncable-secret obj.
+func ownerReferences(meta *SyncableSecretMetaData, obj ctrlclient.Object) []metav1.OwnerReference {
+	return []metav1.OwnerReference{
+		{
+			APIVersion: meta.APIVersion,
+			Kind:       meta.Kind,
+			Name:       obj.GetName(),
+			UID:        obj.GetUID(),
+		},
+	}
+}
+
 // checkSecretIsOwnedByObj validates the Secret is owned by obj by checking its Labels and OwnerReferences.
 func checkSecretIsOwnedByObj(dest *corev1.Secret, references []metav1.OwnerReference) error {
 	var errs error
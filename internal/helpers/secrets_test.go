@@ -0,0 +1,142 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package helpers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	secretsv1alpha1 "github.com/hashicorp/vault-secrets-operator/api/v1alpha1"
+)
+
+func newTestStaticSecret(uid string, create bool) *secretsv1alpha1.VaultStaticSecret {
+	return &secretsv1alpha1.VaultStaticSecret{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "secrets.hashicorp.com/v1alpha1",
+			Kind:       "VaultStaticSecret",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "app",
+			Namespace: "default",
+			UID:       types.UID("uid-" + uid),
+		},
+		Spec: secretsv1alpha1.VaultStaticSecretSpec{
+			Destination: secretsv1alpha1.Destination{
+				Name:   "app",
+				Create: create,
+			},
+		},
+	}
+}
+
+func newOwnedSecret(name string, obj ctrlclient.Object) *corev1.Secret {
+	meta, _ := NewSyncableSecretMetaData(obj)
+	labels := make(map[string]string, len(OwnerLabels))
+	for k, v := range OwnerLabels {
+		labels[k] = v
+	}
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            name,
+			Namespace:       obj.GetNamespace(),
+			Labels:          labels,
+			OwnerReferences: ownerReferences(meta, obj),
+		},
+	}
+}
+
+func TestSyncSecret_WithName(t *testing.T) {
+	ctx := context.Background()
+	data := map[string][]byte{"username": []byte("user")}
+
+	tests := map[string]struct {
+		create  bool
+		name    string
+		wantErr string
+	}{
+		"create": {
+			create: true,
+			name:   "app-db",
+		},
+		"create-destination-name": {
+			create: true,
+			name:   "app",
+		},
+		"no-create": {
+			create:  false,
+			name:    "app-db",
+			wantErr: "syncing to secret app-db requires create=true",
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			c := fake.NewClientBuilder().Build()
+			o := newTestStaticSecret("1", tt.create)
+
+			err := SyncSecret(ctx, c, o, data, WithName(tt.name))
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+
+			var s corev1.Secret
+			require.NoError(t, c.Get(ctx, ctrlclient.ObjectKey{Namespace: "default", Name: tt.name}, &s))
+			assert.Equal(t, data, s.Data)
+			assert.Equal(t, newOwnedSecret(tt.name, o).OwnerReferences, s.OwnerReferences)
+			assert.Equal(t, OwnerLabels, s.Labels)
+		})
+	}
+}
+
+func TestPruneSecrets(t *testing.T) {
+	ctx := context.Background()
+	o := newTestStaticSecret("1", true)
+	other := newTestStaticSecret("2", true)
+
+	unlabeled := newOwnedSecret("unlabeled", o)
+	unlabeled.Labels = nil
+	orphaned := newOwnedSecret("orphaned", o)
+	orphaned.OwnerReferences = nil
+
+	c := fake.NewClientBuilder().WithObjects(
+		newOwnedSecret("app", o),
+		newOwnedSecret("app-db", o),
+		newOwnedSecret("app-old", o),
+		newOwnedSecret("other-app", other),
+		unlabeled,
+		orphaned,
+	).Build()
+
+	require.NoError(t, PruneSecrets(ctx, c, o, "app", "app-db"))
+
+	for name, wantExists := range map[string]bool{
+		// kept
+		"app":    true,
+		"app-db": true,
+		// no longer synced
+		"app-old": false,
+		// not owned by o
+		"other-app": true,
+		"unlabeled": true,
+		"orphaned":  true,
+	} {
+		var s corev1.Secret
+		err := c.Get(ctx, ctrlclient.ObjectKey{Namespace: "default", Name: name}, &s)
+		if wantExists {
+			assert.NoError(t, err, name)
+		} else {
+			assert.True(t, apierrors.IsNotFound(err), "expected %s to be deleted, err=%v", name, err)
+		}
+	}
+}
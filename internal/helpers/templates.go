@@ -0,0 +1,294 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package helpers
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/hashicorp/vault/api"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/hashicorp/vault-secrets-operator/internal/consts"
+)
+
+// TemplateError is returned by RenderTemplates when the Destination's templates
+// could not be loaded or rendered.
+type TemplateError struct {
+	// Key of the Secret whose template failed, empty if the templates could not be loaded.
+	Key string
+	Err error
+}
+
+func (e *TemplateError) Error() string {
+	if e.Key == "" {
+		return fmt.Sprintf("failed to load templates: %s", e.Err)
+	}
+	return fmt.Sprintf("failed to render template for key %q: %s", e.Key, e.Err)
+}
+
+func (e *TemplateError) Unwrap() error {
+	return e.Err
+}
+
+// TemplateInput is the data that the Destination's templates are rendered against.
+type TemplateInput struct {
+	// Secrets is the Vault secret's data.
+	Secrets map[string]any
+	// Metadata of the Vault secret, e.g. the lease of a dynamic secret,
+	// or the version metadata of a kv-v2 secret.
+	Metadata map[string]any
+}
+
+// NewTemplateInputFromSecret returns the TemplateInput for the Vault response s,
+// its lease is provided as the Metadata.
+func NewTemplateInputFromSecret(s *api.Secret) *TemplateInput {
+	return &TemplateInput{
+		Secrets: s.Data,
+		Metadata: map[string]any{
+			"lease_id":       s.LeaseID,
+			"lease_duration": s.LeaseDuration,
+			"renewable":      s.Renewable,
+			"request_id":     s.RequestID,
+		},
+	}
+}
+
+// NewTemplateInputFromKVSecret returns the TemplateInput for the KV secret s,
+// its version and custom metadata are provided as the Metadata.
+func NewTemplateInputFromKVSecret(s *api.KVSecret) *TemplateInput {
+	m := map[string]any{
+		"custom_metadata": s.CustomMetadata,
+	}
+	if v := s.VersionMetadata; v != nil {
+		m["version"] = v.Version
+		m["created_time"] = v.CreatedTime
+		m["deletion_time"] = v.DeletionTime
+		m["destroyed"] = v.Destroyed
+	}
+
+	return &TemplateInput{
+		Secrets:  s.Data,
+		Metadata: m,
+	}
+}
+
+// NewTemplateInputFromData returns the TemplateInput for data that has been
+// assembled from more than one Vault secret, it has no Metadata.
+func NewTemplateInputFromData(data map[string][]byte) *TemplateInput {
+	secrets := make(map[string]any, len(data))
+	for k, v := range data {
+		secrets[k] = string(v)
+	}
+
+	return &TemplateInput{
+		Secrets: secrets,
+	}
+}
+
+// HasTemplates returns true if obj's Spec.Destination configures any templates.
+//
+// See NewSyncableSecretMetaData for the supported types for obj.
+func HasTemplates(obj ctrlclient.Object) bool {
+	m, err := NewSyncableSecretMetaData(obj)
+	if err != nil {
+		return false
+	}
+	return len(m.Destination.Templates) > 0 || m.Destination.TemplatesConfigMap != ""
+}
+
+// RenderTemplates renders obj's Spec.Destination templates against input, and returns
+// a copy of data that includes the rendered keys. data is returned as-is if obj has no templates.
+// Any error is returned as a *TemplateError.
+//
+// See NewSyncableSecretMetaData for the supported types for obj.
+func RenderTemplates(ctx context.Context, client ctrlclient.Client, obj ctrlclient.Object,
+	input *TemplateInput, data map[string][]byte,
+) (map[string][]byte, error) {
+	templates, err := LoadTemplates(ctx, client, obj)
+	if err != nil {
+		return nil, err
+	}
+
+	return ExecuteTemplates(templates, input, data)
+}
+
+// ExecuteTemplates renders templates against input, and returns a copy of data that includes
+// the rendered keys. data is returned as-is if there are no templates.
+// Any error is returned as a *TemplateError.
+func ExecuteTemplates(templates map[string]string, input *TemplateInput, data map[string][]byte) (map[string][]byte, error) {
+	if len(templates) == 0 {
+		return data, nil
+	}
+
+	return renderTemplates(templates, input, data)
+}
+
+// LoadTemplates returns obj's Spec.Destination templates, keyed by the Secret key that
+// they render, including those from the Destination's TemplatesConfigMap.
+// Any error loading the ConfigMap is returned as a *TemplateError.
+//
+// See NewSyncableSecretMetaData for the supported types for obj.
+func LoadTemplates(ctx context.Context, client ctrlclient.Client, obj ctrlclient.Object) (map[string]string, error) {
+	m, err := NewSyncableSecretMetaData(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	templates := make(map[string]string, len(m.Destination.Templates))
+	if m.Destination.TemplatesConfigMap != "" {
+		var cm corev1.ConfigMap
+		key := ctrlclient.ObjectKey{Namespace: obj.GetNamespace(), Name: m.Destination.TemplatesConfigMap}
+		if err := client.Get(ctx, key, &cm); err != nil {
+			return nil, &TemplateError{Err: err}
+		}
+		for k, v := range cm.Data {
+			templates[k] = v
+		}
+	}
+	for k, v := range m.Destination.Templates {
+		templates[k] = v
+	}
+
+	return templates, nil
+}
+
+func renderTemplates(templates map[string]string, input *TemplateInput, data map[string][]byte) (map[string][]byte, error) {
+	keys := make([]string, 0, len(templates))
+	for k := range templates {
+		keys = append(keys, k)
+	}
+	// sorted for deterministic error reporting
+	sort.Strings(keys)
+
+	result := make(map[string][]byte, len(data)+len(templates))
+	for k, v := range data {
+		result[k] = v
+	}
+	for _, k := range keys {
+		// the keys of a ConfigMap's data are not necessarily valid Secret keys.
+		if errs := validation.IsConfigMapKey(k); len(errs) > 0 {
+			return nil, &TemplateError{Key: k, Err: fmt.Errorf("invalid Secret key: %s", strings.Join(errs, ", "))}
+		}
+
+		t, err := template.New(k).Option("missingkey=error").Funcs(templateFuncs).Parse(templates[k])
+		if err != nil {
+			return nil, &TemplateError{Key: k, Err: err}
+		}
+
+		var b bytes.Buffer
+		if err := t.Execute(&b, input); err != nil {
+			return nil, &TemplateError{Key: k, Err: err}
+		}
+		result[k] = b.Bytes()
+	}
+
+	return result, nil
+}
+
+// SetTemplatesRenderedCondition sets the TemplatesRendered condition in conditions from the
+// result of RenderTemplates. The condition is removed if obj has no templates.
+func SetTemplatesRenderedCondition(obj ctrlclient.Object, conditions *[]metav1.Condition, err error) {
+	if err == nil && !HasTemplates(obj) {
+		meta.RemoveStatusCondition(conditions, consts.TypeTemplatesRendered)
+		return
+	}
+
+	c := metav1.Condition{
+		Type:               consts.TypeTemplatesRendered,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: obj.GetGeneration(),
+		Reason:             consts.ReasonTemplatesRendered,
+		Message:            "Templates rendered",
+	}
+	if err != nil {
+		c.Status = metav1.ConditionFalse
+		c.Reason = consts.ReasonTemplateError
+		c.Message = err.Error()
+	}
+	meta.SetStatusCondition(conditions, c)
+}
+
+// templateFuncs are a selection of Sprig compatible helper functions. Only deterministic
+// functions are included, since the rendered data is compared to that of the destination Secret.
+var templateFuncs = template.FuncMap{
+	"b64enc": func(s string) string {
+		return base64.StdEncoding.EncodeToString([]byte(s))
+	},
+	"b64dec": func(s string) (string, error) {
+		b, err := base64.StdEncoding.DecodeString(s)
+		return string(b), err
+	},
+	"contains":   func(substr, s string) bool { return strings.Contains(s, substr) },
+	"default":    templateDefault,
+	"hasPrefix":  func(prefix, s string) bool { return strings.HasPrefix(s, prefix) },
+	"hasSuffix":  func(suffix, s string) bool { return strings.HasSuffix(s, suffix) },
+	"indent":     templateIndent,
+	"join":       templateJoin,
+	"lower":      strings.ToLower,
+	"nindent":    func(n int, s string) string { return "\n" + templateIndent(n, s) },
+	"quote":      func(v any) string { return fmt.Sprintf("%q", fmt.Sprint(v)) },
+	"replace":    func(old, new, s string) string { return strings.ReplaceAll(s, old, new) },
+	"squote":     func(v any) string { return "'" + fmt.Sprint(v) + "'" },
+	"toJson":     templateToJSON,
+	"trim":       strings.TrimSpace,
+	"trimPrefix": func(prefix, s string) string { return strings.TrimPrefix(s, prefix) },
+	"trimSuffix": func(suffix, s string) string { return strings.TrimSuffix(s, suffix) },
+	"upper":      strings.ToUpper,
+}
+
+// templateDefault returns d if v is empty, e.g. {{ index .Secrets "port" | default "5432" }}
+func templateDefault(d any, v ...any) any {
+	if len(v) == 0 || v[0] == nil {
+		return d
+	}
+	rv := reflect.ValueOf(v[0])
+	if rv.IsZero() {
+		return d
+	}
+	switch rv.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice:
+		if rv.Len() == 0 {
+			return d
+		}
+	}
+	return v[0]
+}
+
+func templateIndent(n int, s string) string {
+	pad := strings.Repeat(" ", n)
+	return pad + strings.ReplaceAll(s, "\n", "\n"+pad)
+}
+
+func templateJoin(sep string, v any) (string, error) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Array && rv.Kind() != reflect.Slice {
+		return "", fmt.Errorf("join: unsupported type %T", v)
+	}
+
+	s := make([]string, rv.Len())
+	for i := range s {
+		s[i] = fmt.Sprint(rv.Index(i).Interface())
+	}
+	return strings.Join(s, sep), nil
+}
+
+func templateToJSON(v any) (string, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
@@ -0,0 +1,178 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package helpers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/vault/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	secretsv1alpha1 "github.com/hashicorp/vault-secrets-operator/api/v1alpha1"
+	"github.com/hashicorp/vault-secrets-operator/internal/consts"
+)
+
+func Test_renderTemplates(t *testing.T) {
+	input := NewTemplateInputFromSecret(&api.Secret{
+		LeaseID:       "db/creds/app/1234",
+		LeaseDuration: 3600,
+		Data: map[string]any{
+			"username": "user",
+			"password": "p@ss",
+			"hosts":    []any{"db-0", "db-1"},
+		},
+	})
+	data := map[string][]byte{
+		"username": []byte("user"),
+		"password": []byte("p@ss"),
+	}
+
+	tests := map[string]struct {
+		templates map[string]string
+		expected  map[string][]byte
+		wantErr   string
+	}{
+		"connection string": {
+			templates: map[string]string{
+				"url": `postgres://{{ .Secrets.username }}:{{ .Secrets.password }}@{{ join "," .Secrets.hosts }}/app`,
+			},
+			expected: map[string][]byte{
+				"username": []byte("user"),
+				"password": []byte("p@ss"),
+				"url":      []byte("postgres://user:p@ss@db-0,db-1/app"),
+			},
+		},
+		"metadata and helpers": {
+			templates: map[string]string{
+				"lease":    `{{ .Metadata.lease_id | trimPrefix "db/creds/" }}/{{ .Metadata.lease_duration }}`,
+				"password": `{{ .Secrets.password | b64enc }}`,
+				"port":     `{{ index .Secrets "port" | default "5432" }}`,
+				"user":     `{{ .Secrets.username | upper | quote }}`,
+			},
+			expected: map[string][]byte{
+				"username": []byte("user"),
+				"password": []byte("cEBzcw=="),
+				"lease":    []byte("app/1234/3600"),
+				"port":     []byte("5432"),
+				"user":     []byte(`"USER"`),
+			},
+		},
+		"config file": {
+			templates: map[string]string{
+				"config.yaml": "db:{{ .Secrets | toJson | nindent 2 }}",
+			},
+			expected: map[string][]byte{
+				"username":    []byte("user"),
+				"password":    []byte("p@ss"),
+				"config.yaml": []byte("db:\n  {\"hosts\":[\"db-0\",\"db-1\"],\"password\":\"p@ss\",\"username\":\"user\"}"),
+			},
+		},
+		"missing key": {
+			templates: map[string]string{
+				"url": `{{ .Secrets.host }}`,
+			},
+			wantErr: `failed to render template for key "url": template: url:1:11: executing "url" at <.Secrets.host>: map has no entry for key "host"`,
+		},
+		"parse error": {
+			templates: map[string]string{
+				"url": `{{ .Secrets.username `,
+			},
+			wantErr: `failed to render template for key "url": template: url:1: unclosed action`,
+		},
+		"invalid key": {
+			templates: map[string]string{
+				"db/url": `{{ .Secrets.username }}`,
+			},
+			wantErr: `failed to render template for key "db/url": invalid Secret key: ` +
+				`a valid config key must consist of alphanumeric characters, '-', '_' or '.' ` +
+				`(e.g. 'key.name',  or 'KEY_NAME',  or 'key-name', regex used for validation is '[-._a-zA-Z0-9]+')`,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := renderTemplates(tt.templates, input, data)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				var tplErr *TemplateError
+				assert.ErrorAs(t, err, &tplErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, got)
+		})
+	}
+}
+
+func TestRenderTemplates(t *testing.T) {
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "templates",
+			Namespace: "default",
+		},
+		Data: map[string]string{
+			"url":  "from-configmap",
+			"user": "{{ .Secrets.username }}",
+		},
+	}
+	c := fake.NewClientBuilder().WithObjects(cm).Build()
+
+	o := &secretsv1alpha1.VaultStaticSecret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "app",
+			Namespace:  "default",
+			Generation: 2,
+		},
+		Spec: secretsv1alpha1.VaultStaticSecretSpec{
+			Destination: secretsv1alpha1.Destination{
+				Name:               "app",
+				TemplatesConfigMap: "templates",
+				Templates: map[string]string{
+					"url": "from-spec",
+				},
+			},
+		},
+	}
+	input := NewTemplateInputFromKVSecret(&api.KVSecret{
+		Data: map[string]any{"username": "user"},
+	})
+
+	got, err := RenderTemplates(context.Background(), c, o, input, nil)
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]byte{
+		"url":  []byte("from-spec"),
+		"user": []byte("user"),
+	}, got)
+
+	SetTemplatesRenderedCondition(o, &o.Status.Conditions, err)
+	assert.True(t, meta.IsStatusConditionTrue(o.Status.Conditions, consts.TypeTemplatesRendered))
+
+	o.Spec.Destination.TemplatesConfigMap = "missing"
+	_, err = RenderTemplates(context.Background(), c, o, input, nil)
+	assert.EqualError(t, err, `failed to load templates: configmaps "missing" not found`)
+
+	SetTemplatesRenderedCondition(o, &o.Status.Conditions, err)
+	cond := meta.FindStatusCondition(o.Status.Conditions, consts.TypeTemplatesRendered)
+	require.NotNil(t, cond)
+	assert.Equal(t, metav1.ConditionFalse, cond.Status)
+	assert.Equal(t, consts.ReasonTemplateError, cond.Reason)
+	assert.Equal(t, int64(2), cond.ObservedGeneration)
+
+	// the condition is removed once no templates are configured.
+	o.Spec.Destination.Templates = nil
+	o.Spec.Destination.TemplatesConfigMap = ""
+	data := map[string][]byte{"username": []byte("user")}
+	got, err = RenderTemplates(context.Background(), c, o, input, data)
+	require.NoError(t, err)
+	assert.Equal(t, data, got)
+
+	SetTemplatesRenderedCondition(o, &o.Status.Conditions, err)
+	assert.Empty(t, o.Status.Conditions)
+}
@@ -0,0 +1,110 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package helpers
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	secretsv1alpha1 "github.com/hashicorp/vault-secrets-operator/api/v1alpha1"
+)
+
+// rawKey is the key of the secret data that holds the complete Vault secret data.
+const rawKey = "_raw"
+
+// TransformSecretData applies the obj's Spec.Destination.Transformation to data,
+// returning the transformed data. data is returned as-is if obj has no Transformation.
+// It must be called before the data is compared against the destination Secret's data,
+// and before it is passed to SyncSecret.
+//
+// See NewSyncableSecretMetaData for the supported types for obj.
+func TransformSecretData(obj ctrlclient.Object, data map[string][]byte) (map[string][]byte, error) {
+	meta, err := NewSyncableSecretMetaData(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	return transformSecretData(meta.Destination.Transformation, data)
+}
+
+func transformSecretData(t *secretsv1alpha1.Transformation, data map[string][]byte) (map[string][]byte, error) {
+	if t == nil {
+		return data, nil
+	}
+
+	for _, pattern := range append(append([]string{}, t.Includes...), t.Excludes...) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid transformation pattern %q: %w", pattern, err)
+		}
+	}
+
+	keys := make([]string, 0, len(data))
+	for k := range data {
+		keys = append(keys, k)
+	}
+	// sorted for deterministic error reporting
+	sort.Strings(keys)
+
+	result := make(map[string][]byte, len(data))
+	renamedFrom := make(map[string]string, len(data))
+	// the raw secret contains all the Vault secret's data, so it must be dropped
+	// whenever any other key is excluded or renamed.
+	var dropRaw bool
+	var rawName string
+	for _, k := range keys {
+		if t.ExcludeRaw && k == rawKey {
+			continue
+		}
+		if len(t.Includes) > 0 && !matchAny(t.Includes, k) || matchAny(t.Excludes, k) {
+			dropRaw = dropRaw || k != rawKey
+			continue
+		}
+
+		name := k
+		if n, ok := t.Renames[k]; ok {
+			name = n
+		}
+		if name == "" {
+			return nil, fmt.Errorf("key %q is renamed to an empty key", k)
+		}
+		if name != k {
+			if errs := validation.IsConfigMapKey(name); len(errs) > 0 {
+				return nil, fmt.Errorf("key %q is renamed to an invalid key %q: %s", k, name, strings.Join(errs, ", "))
+			}
+		}
+		if other, ok := renamedFrom[name]; ok {
+			return nil, fmt.Errorf("keys %q and %q are both transformed to key %q", other, k, name)
+		}
+
+		if k == rawKey {
+			rawName = name
+		} else if name != k {
+			dropRaw = true
+		}
+		renamedFrom[name] = k
+		result[name] = data[k]
+	}
+
+	if rawName != "" && dropRaw {
+		delete(result, rawName)
+	}
+
+	return result, nil
+}
+
+// matchAny returns true if the key matches any of the patterns,
+// the patterns must already be validated.
+func matchAny(patterns []string, key string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, key); ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,162 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package helpers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	secretsv1alpha1 "github.com/hashicorp/vault-secrets-operator/api/v1alpha1"
+)
+
+func Test_transformSecretData(t *testing.T) {
+	data := map[string][]byte{
+		"db_username": []byte("user"),
+		"db_password": []byte("pass"),
+		"api_token":   []byte("token"),
+		"_raw":        []byte(`{"db_username":"user","db_password":"pass","api_token":"token"}`),
+	}
+
+	tests := map[string]struct {
+		transformation *secretsv1alpha1.Transformation
+		expected       map[string][]byte
+		wantErr        string
+	}{
+		"nil transformation": {
+			expected: data,
+		},
+		"exclude raw": {
+			transformation: &secretsv1alpha1.Transformation{
+				ExcludeRaw: true,
+			},
+			expected: map[string][]byte{
+				"db_username": []byte("user"),
+				"db_password": []byte("pass"),
+				"api_token":   []byte("token"),
+			},
+		},
+		"includes": {
+			transformation: &secretsv1alpha1.Transformation{
+				Includes: []string{"db_*"},
+			},
+			expected: map[string][]byte{
+				"db_username": []byte("user"),
+				"db_password": []byte("pass"),
+			},
+		},
+		"includes and excludes": {
+			transformation: &secretsv1alpha1.Transformation{
+				Includes: []string{"db_*", "api_token"},
+				Excludes: []string{"*_password"},
+			},
+			expected: map[string][]byte{
+				"db_username": []byte("user"),
+				"api_token":   []byte("token"),
+			},
+		},
+		"renames": {
+			transformation: &secretsv1alpha1.Transformation{
+				Excludes:   []string{"api_*"},
+				ExcludeRaw: true,
+				Renames: map[string]string{
+					"db_username": "username",
+					"db_password": "password",
+					"api_token":   "token",
+				},
+			},
+			expected: map[string][]byte{
+				"username": []byte("user"),
+				"password": []byte("pass"),
+			},
+		},
+		"excludes drop raw": {
+			transformation: &secretsv1alpha1.Transformation{
+				Excludes: []string{"api_*"},
+			},
+			expected: map[string][]byte{
+				"db_username": []byte("user"),
+				"db_password": []byte("pass"),
+			},
+		},
+		"included raw is dropped with excluded keys": {
+			transformation: &secretsv1alpha1.Transformation{
+				Includes: []string{"db_*", "_raw"},
+			},
+			expected: map[string][]byte{
+				"db_username": []byte("user"),
+				"db_password": []byte("pass"),
+			},
+		},
+		"renames drop raw": {
+			transformation: &secretsv1alpha1.Transformation{
+				Renames: map[string]string{
+					"db_username": "username",
+				},
+			},
+			expected: map[string][]byte{
+				"username":    []byte("user"),
+				"db_password": []byte("pass"),
+				"api_token":   []byte("token"),
+			},
+		},
+		"rename raw": {
+			transformation: &secretsv1alpha1.Transformation{
+				Renames: map[string]string{
+					"_raw": "raw.json",
+				},
+			},
+			expected: map[string][]byte{
+				"db_username": []byte("user"),
+				"db_password": []byte("pass"),
+				"api_token":   []byte("token"),
+				"raw.json":    data["_raw"],
+			},
+		},
+		"rename invalid": {
+			transformation: &secretsv1alpha1.Transformation{
+				Renames: map[string]string{
+					"db_username": "db/username",
+				},
+			},
+			wantErr: `key "db_username" is renamed to an invalid key "db/username": ` +
+				`a valid config key must consist of alphanumeric characters, '-', '_' or '.' ` +
+				`(e.g. 'key.name',  or 'KEY_NAME',  or 'key-name', regex used for validation is '[-._a-zA-Z0-9]+')`,
+		},
+		"rename conflict": {
+			transformation: &secretsv1alpha1.Transformation{
+				Renames: map[string]string{
+					"db_username": "api_token",
+				},
+			},
+			wantErr: `keys "api_token" and "db_username" are both transformed to key "api_token"`,
+		},
+		"rename empty": {
+			transformation: &secretsv1alpha1.Transformation{
+				Renames: map[string]string{
+					"db_username": "",
+				},
+			},
+			wantErr: `key "db_username" is renamed to an empty key`,
+		},
+		"invalid pattern": {
+			transformation: &secretsv1alpha1.Transformation{
+				Excludes: []string{"db_["},
+			},
+			wantErr: `invalid transformation pattern "db_[": syntax error in pattern`,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := transformSecretData(tt.transformation, data)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, got)
+		})
+	}
+}
@@ -4,6 +4,8 @@
 package metrics
 
 import (
+	"time"
+
 	"github.com/prometheus/client_golang/prometheus"
 	apimachineryversion "k8s.io/apimachinery/pkg/version"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -18,6 +20,10 @@ const (
 	// LabelVaultConnection usually contains the full name of VaultConnection CR.
 	// e.g. namespace1/connection1
 	LabelVaultConnection = "vault_connection"
+	// LabelProvider contains the credential provider's auth method.
+	LabelProvider = "provider"
+	// LabelVaultAddress contains the address of a Vault server.
+	LabelVaultAddress = "vault_address"
 
 	OperationGet        = "get"
 	OperationStore      = "store"
@@ -28,13 +34,16 @@ const (
 	OperationLogin      = "login"
 	OperationRenew      = "renew"
 	OperationRead       = "read"
+	OperationList       = "list"
 	OperationWrite      = "write"
+	OperationUnwrap     = "unwrap"
 
 	NameConfig                = "config"
 	NameLength                = "length"
 	NameOperationsTotal       = "operations_total"
 	NameOperationsErrorsTotal = "operations_errors_total"
 	NameOperationsTimeSeconds = "operations_time_seconds"
+	NameThrottleTimeSeconds   = "throttle_time_seconds"
 	NameRequestsTotal         = "requests_total"
 	NameRequestsErrorsTotal   = "requests_errors_total"
 )
@@ -48,9 +57,30 @@ var ResourceStatus = prometheus.NewGaugeVec(prometheus.GaugeOpts{
 	"namespace",
 })
 
+var VaultConnectionHealth = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	Namespace: Namespace,
+	Subsystem: "vault_connection",
+	Name:      "healthy",
+	Help:      "Health of a VaultConnection's Vault server; a value other than 1 denotes an unhealthy server",
+}, []string{
+	LabelVaultConnection,
+})
+
+var VaultConnectionHealthCheckTime = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	Namespace: Namespace,
+	Subsystem: "vault_connection",
+	Name:      "health_check_time_seconds",
+	Help:      "Duration of the last health check of each of a VaultConnection's Vault addresses",
+}, []string{
+	LabelVaultConnection,
+	LabelVaultAddress,
+})
+
 func init() {
 	metrics.Registry.MustRegister(
 		ResourceStatus,
+		VaultConnectionHealth,
+		VaultConnectionHealthCheckTime,
 	)
 }
 
@@ -65,6 +95,38 @@ func SetResourceStatus(controller string, o client.Object, valid bool) {
 	}
 }
 
+// SetVaultConnectionHealth for the given VaultConnection. If healthy is true,
+// then the VaultConnectionHealth gauge will be set 1, else 0.
+func SetVaultConnectionHealth(o client.Object, healthy bool) {
+	g := VaultConnectionHealth.WithLabelValues(client.ObjectKeyFromObject(o).String())
+	if healthy {
+		g.Set(float64(1))
+	} else {
+		g.Set(float64(0))
+	}
+}
+
+// DeleteVaultConnectionHealth removes the VaultConnectionHealth gauge of the
+// given VaultConnection.
+func DeleteVaultConnectionHealth(o client.Object) {
+	VaultConnectionHealth.DeleteLabelValues(client.ObjectKeyFromObject(o).String())
+}
+
+// SetVaultConnectionHealthCheckTime sets the VaultConnectionHealthCheckTime gauge
+// of the given VaultConnection's Vault address to d.
+func SetVaultConnectionHealthCheckTime(o client.Object, address string, d time.Duration) {
+	VaultConnectionHealthCheckTime.WithLabelValues(
+		client.ObjectKeyFromObject(o).String(), address).Set(d.Seconds())
+}
+
+// DeleteVaultConnectionHealthCheckTime removes the VaultConnectionHealthCheckTime gauges
+// of all the given VaultConnection's Vault addresses.
+func DeleteVaultConnectionHealthCheckTime(o client.Object) {
+	VaultConnectionHealthCheckTime.DeletePartialMatch(prometheus.Labels{
+		LabelVaultConnection: client.ObjectKeyFromObject(o).String(),
+	})
+}
+
 // NewBuildInfoGauge provides the Operator's build info as a Prometheus metric.
 func NewBuildInfoGauge(info apimachineryversion.Info) prometheus.Gauge {
 	metric := prometheus.NewGauge(
@@ -25,7 +25,7 @@ var (
 	errorInvalidUIDLength  = errors.New("invalid UID length")
 	cacheKeyRe             = regexp.MustCompile(fmt.Sprintf(
 		`(%s)-[[:xdigit:]]{22}`,
-		strings.Join(credentials.ProviderMethodsSupported, "|")))
+		strings.ToLower(strings.Join(credentials.ProviderMethodsSupported, "|"))))
 	cloneKeyRe = regexp.MustCompile(fmt.Sprintf(`^%s-.+$`, cacheKeyRe))
 )
 
@@ -86,7 +86,7 @@ func ComputeClientCacheKeyFromObj(ctx context.Context, client ctrlclient.Client,
 // from three different sources as inputs.
 //
 // The resulting key will resemble something like: kubernetes-2a8108711ae49ac0faa724, where the prefix
-// is the VaultAuth.Spec.Method, and the remainder is the concatenation of the
+// is the lower-cased VaultAuth.Spec.Method, and the remainder is the concatenation of the
 // first 7 and last 4 bytes of the computed SHA256 check-sum in hex.
 //
 // The key is included in the name of the corev1.Secrets created by the ClientCacheStorage,
@@ -128,7 +128,7 @@ func computeClientCacheKey(authObj *secretsv1alpha1.VaultAuth, connObj *secretsv
 		connObj.GetUID(), connObj.GetGeneration(), providerUID)
 
 	sum := sha256.Sum256([]byte(input))
-	key := method + "-" + fmt.Sprintf("%x%x", sum[0:7], sum[len(sum)-4:])
+	key := strings.ToLower(method) + "-" + fmt.Sprintf("%x%x", sum[0:7], sum[len(sum)-4:])
 	if len(key) > 63 {
 		return "", errorKeyLengthExceeded
 	}
@@ -56,6 +56,27 @@ func Test_computeClientCacheKey(t *testing.T) {
 			want:        "ical-" + computedHash,
 			wantErr:     assert.NoError,
 		},
+		{
+			name: "valid-method-lower-cased",
+			authObj: &secretsv1alpha1.VaultAuth{
+				ObjectMeta: metav1.ObjectMeta{
+					UID:        authUID,
+					Generation: 0,
+				},
+				Spec: secretsv1alpha1.VaultAuthSpec{
+					Method: credentials.ProviderMethodAppRole,
+				},
+			},
+			connObj: &secretsv1alpha1.VaultConnection{
+				ObjectMeta: metav1.ObjectMeta{
+					UID:        connUID,
+					Generation: 0,
+				},
+			},
+			providerUID: providerUID,
+			want:        "approle-" + computedHash,
+			wantErr:     assert.NoError,
+		},
 		{
 			name: "valid-key-at-max-length",
 			authObj: &secretsv1alpha1.VaultAuth{
@@ -245,6 +266,13 @@ func TestClientCacheKey_IsClone(t *testing.T) {
 				computedHash)),
 			want: false,
 		},
+		{
+			name: "is-a-clone-approle",
+			k: ClientCacheKey(fmt.Sprintf("%s-%s-ns1/ns2",
+				"approle",
+				computedHash)),
+			want: true,
+		},
 		{
 			name: "is-a-clone",
 			k: ClientCacheKey(fmt.Sprintf("%s-%s-ns1/ns2",
@@ -5,12 +5,15 @@ package vault
 
 import (
 	"context"
+	"crypto/tls"
 	"errors"
 	"fmt"
+	"net/url"
 	"sync"
 	"time"
 
 	"github.com/hashicorp/vault/api"
+	"k8s.io/apimachinery/pkg/types"
 	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 
@@ -134,6 +137,9 @@ type Client interface {
 	Init(context.Context, ctrlclient.Client, *secretsv1alpha1.VaultAuth, *secretsv1alpha1.VaultConnection, string, *ClientOptions) error
 	Login(context.Context, ctrlclient.Client) error
 	Read(context.Context, string) (*api.Secret, error)
+	ReadWithData(context.Context, string, map[string][]string) (*api.Secret, error)
+	List(context.Context, string) (*api.Secret, error)
+	ReadControlGroup(context.Context, types.UID, string) (*api.Secret, string, error)
 	Restore(context.Context, *api.Secret) error
 	Write(context.Context, string, map[string]any) (*api.Secret, error)
 	GetTokenSecret() *api.Secret
@@ -142,8 +148,6 @@ type Client interface {
 	GetVaultConnectionObj() *secretsv1alpha1.VaultConnection
 	GetCredentialProvider() credentials.CredentialProvider
 	GetCacheKey() (ClientCacheKey, error)
-	KVv1(string) (*api.KVv1, error)
-	KVv2(string) (*api.KVv2, error)
 	Close()
 	Clone(string) (Client, error)
 	IsClone() bool
@@ -163,6 +167,7 @@ type defaultClient struct {
 	lastRenewal        int64
 	targetNamespace    string
 	credentialProvider credentials.CredentialProvider
+	limiter            *connectionLimiter
 	watcher            *api.LifetimeWatcher
 	lastWatcherErr     error
 	once               sync.Once
@@ -206,6 +211,7 @@ func (c *defaultClient) Clone(namespace string) (Client, error) {
 		skipRenewal:        true,
 		targetNamespace:    c.targetNamespace,
 		credentialProvider: c.credentialProvider,
+		limiter:            c.limiter,
 	}
 	client.SetNamespace(namespace)
 
@@ -216,14 +222,6 @@ func (c *defaultClient) GetCredentialProvider() credentials.CredentialProvider {
 	return c.credentialProvider
 }
 
-func (c *defaultClient) KVv1(mount string) (*api.KVv1, error) {
-	return c.client.KVv1(mount), nil
-}
-
-func (c *defaultClient) KVv2(mount string) (*api.KVv2, error) {
-	return c.client.KVv2(mount), nil
-}
-
 func (c *defaultClient) GetCacheKey() (ClientCacheKey, error) {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
@@ -301,11 +299,21 @@ func (c *defaultClient) CheckExpiry(offset int64) (bool, error) {
 }
 
 func (c *defaultClient) checkExpiry(offset int64) (bool, error) {
+	if c.authObj != nil && c.authObj.Spec.Method == credentials.ProviderMethodAutoAuth {
+		// the auto-auth token is managed by the Vault Agent or Vault Proxy.
+		return false, nil
+	}
+
 	ttl, err := c.getTokenTTL()
 	if err != nil {
 		return false, err
 	}
 
+	if ttl == 0 && c.authObj != nil && c.authObj.Spec.Method == credentials.ProviderMethodToken {
+		// a static token without a TTL never expires, e.g. a root token.
+		return false, nil
+	}
+
 	horizon := ttl - time.Second*time.Duration(offset)
 	if horizon < 1 {
 		// will always result in expiry
@@ -352,7 +360,12 @@ func (c *defaultClient) startLifetimeWatcher(ctx context.Context) error {
 		return fmt.Errorf("lifetimeWatcher already started")
 	}
 
-	watcher, err := c.client.NewLifetimeWatcher(&api.LifetimeWatcherInput{
+	renewalClient, err := c.newRenewalClient()
+	if err != nil {
+		return err
+	}
+
+	watcher, err := renewalClient.NewLifetimeWatcher(&api.LifetimeWatcherInput{
 		Secret: c.authSecret,
 	})
 	if err != nil {
@@ -393,6 +406,35 @@ func (c *defaultClient) startLifetimeWatcher(ctx context.Context) error {
 	return nil
 }
 
+// newRenewalClient returns a copy of the Client's Vault client for the api.LifetimeWatcher.
+// The LifetimeWatcher renews the token with the Vault client directly, so the copy's transport
+// is throttled instead, ensuring that the renewals are subject to the VaultConnection's limits.
+func (c *defaultClient) newRenewalClient() (*api.Client, error) {
+	if c.limiter == nil {
+		return c.client, nil
+	}
+
+	config := c.client.CloneConfig()
+	httpClient := *config.HttpClient
+	httpClient.Transport = &throttledTransport{
+		base: httpClient.Transport,
+		throttle: func(ctx context.Context) (func(), error) {
+			return c.throttle(ctx, metrics.OperationRenew)
+		},
+	}
+	config.HttpClient = &httpClient
+
+	vc, err := api.NewClient(config)
+	if err != nil {
+		return nil, err
+	}
+	vc.SetToken(c.client.Token())
+	// includes the Vault namespace header.
+	vc.SetHeaders(c.client.Headers())
+
+	return vc, nil
+}
+
 // Login the Client to Vault. Upon success, if the auth token is renewable,
 // an api.LifetimeWatcher will be started to ensure that the token is periodically renewed.
 func (c *defaultClient) Login(ctx context.Context, client ctrlclient.Client) error {
@@ -409,28 +451,76 @@ func (c *defaultClient) Login(ctx context.Context, client ctrlclient.Client) err
 		c.watcher.Stop()
 	}
 
+	certProvider, isCertProvider := c.credentialProvider.(credentials.ClientCertificateProvider)
+	var lastCert *tls.Certificate
+	if isCertProvider {
+		lastCert, _ = certProvider.GetClientCertificate(nil)
+	}
+
+	if c.authObj.Spec.Method == credentials.ProviderMethodAutoAuth {
+		// the Vault Agent or Vault Proxy injects its own auto-auth token into every request.
+		c.client.ClearToken()
+		c.authSecret = &api.Secret{Auth: &api.SecretAuth{}}
+		c.lastRenewal = time.Now().Unix()
+		return nil
+	}
+
 	creds, err := c.credentialProvider.GetCreds(ctx, client)
 	if err != nil {
 		errs = err
 		return errs
 	}
 
-	if len(c.authObj.Spec.Headers) > 0 {
+	if err := c.unwrapCreds(ctx, creds); err != nil {
+		errs = err
+		return errs
+	}
+
+	if isCertProvider {
+		// the client certificate was rotated, drop any idle connections so that
+		// the login request is made over a new TLS session presenting the new certificate.
+		if cert, _ := certProvider.GetClientCertificate(nil); cert != lastCert {
+			c.client.CloneConfig().HttpClient.CloseIdleConnections()
+		}
+	}
+
+	loginHeaders, err := c.loginHeaders(ctx, client)
+	if err != nil {
+		errs = err
+		return errs
+	}
+	if len(loginHeaders) > 0 {
 		defer c.client.SetHeaders(c.client.Headers())
 		headers := c.client.Headers()
-		for k, v := range c.authObj.Spec.Headers {
+		for k, v := range loginHeaders {
 			headers[k] = []string{v}
 		}
 		c.client.SetHeaders(headers)
 	}
 
-	path := fmt.Sprintf("auth/%s/login", c.authObj.Spec.Mount)
-	resp, err := c.Write(ctx, path, creds)
+	var resp *api.Secret
+	if c.authObj.Spec.Method == credentials.ProviderMethodToken {
+		// static tokens do not require a login, the token's auth details are looked up instead.
+		resp, err = c.lookupSelf(ctx, creds)
+	} else {
+		resp, err = c.Write(ctx, loginPath(c.authObj, creds), creds)
+	}
 	if err != nil {
 		errs = err
 		return errs
 	}
 
+	if resp == nil || resp.Auth == nil {
+		errs = fmt.Errorf("empty auth response from Vault")
+		return errs
+	}
+	if resp.Auth.MFARequirement != nil {
+		// login MFA requires an interactive second factor, which can never be provided.
+		errs = fmt.Errorf("login requires MFA which is not supported, mfa_request_id=%s",
+			resp.Auth.MFARequirement.MFARequestID)
+		return errs
+	}
+
 	c.client.SetToken(resp.Auth.ClientToken)
 
 	c.authSecret = resp
@@ -446,6 +536,89 @@ func (c *defaultClient) Login(ctx context.Context, client ctrlclient.Client) err
 	return nil
 }
 
+// loginHeaders returns the VaultAuth's headers that are only included in the login request,
+// the values of the HeadersFrom secrets take precedence over Headers.
+func (c *defaultClient) loginHeaders(ctx context.Context, client ctrlclient.Client) (map[string]string, error) {
+	if len(c.authObj.Spec.HeadersFrom) == 0 {
+		return c.authObj.Spec.Headers, nil
+	}
+
+	headersFrom, err := getHeadersFromSecrets(ctx, client, c.authObj.Namespace, c.authObj.Spec.HeadersFrom)
+	if err != nil {
+		return nil, err
+	}
+	headers := make(map[string]string, len(c.authObj.Spec.Headers)+len(headersFrom))
+	for _, h := range []map[string]string{c.authObj.Spec.Headers, headersFrom} {
+		for k, v := range h {
+			headers[k] = v
+		}
+	}
+	return headers, nil
+}
+
+// loginPath returns the Vault API path used to log in via authObj's auth method.
+func loginPath(authObj *secretsv1alpha1.VaultAuth, creds map[string]any) string {
+	path := fmt.Sprintf("auth/%s/login", authObj.Spec.Mount)
+	switch authObj.Spec.Method {
+	case credentials.ProviderMethodUserPass, credentials.ProviderMethodLDAP:
+		// the username is part of the login path for these methods, it is escaped
+		// so that it can never change the path.
+		path = fmt.Sprintf("%s/%s", path, url.PathEscape(fmt.Sprint(creds[credentials.UsernameCredsKey])))
+	}
+	return path
+}
+
+// lookupSelf returns an auth api.Secret for the static Vault token found in creds.
+// It should be called from a write locked method only.
+func (c *defaultClient) lookupSelf(ctx context.Context, creds map[string]any) (*api.Secret, error) {
+	token, ok := creds[credentials.TokenCredsKey].(string)
+	if !ok || token == "" {
+		return nil, fmt.Errorf("no Vault token found in the credentials")
+	}
+
+	c.client.SetToken(token)
+	resp, err := c.Read(ctx, "auth/token/lookup-self")
+	if err != nil {
+		c.client.ClearToken()
+		return nil, err
+	}
+	if resp == nil || resp.Data == nil {
+		c.client.ClearToken()
+		return nil, fmt.Errorf("empty response from token lookup-self")
+	}
+
+	accessor, err := resp.TokenAccessor()
+	if err != nil {
+		return nil, err
+	}
+	policies, err := resp.TokenPolicies()
+	if err != nil {
+		return nil, err
+	}
+	renewable, err := resp.TokenIsRenewable()
+	if err != nil {
+		return nil, err
+	}
+	ttl, err := resp.TokenTTL()
+	if err != nil {
+		return nil, err
+	}
+	entityID, _ := resp.Data["entity_id"].(string)
+
+	return &api.Secret{
+		RequestID: resp.RequestID,
+		Auth: &api.SecretAuth{
+			ClientToken:   token,
+			Accessor:      accessor,
+			Policies:      policies,
+			TokenPolicies: policies,
+			Renewable:     renewable,
+			LeaseDuration: int(ttl.Seconds()),
+			EntityID:      entityID,
+		},
+	}, nil
+}
+
 func (c *defaultClient) GetVaultAuthObj() *secretsv1alpha1.VaultAuth {
 	return c.authObj
 }
@@ -455,6 +628,10 @@ func (c *defaultClient) GetVaultConnectionObj() *secretsv1alpha1.VaultConnection
 }
 
 func (c *defaultClient) Read(ctx context.Context, path string) (*api.Secret, error) {
+	return c.ReadWithData(ctx, path, nil)
+}
+
+func (c *defaultClient) ReadWithData(ctx context.Context, path string, data map[string][]string) (*api.Secret, error) {
 	var err error
 	startTS := time.Now()
 	defer func() {
@@ -462,8 +639,44 @@ func (c *defaultClient) Read(ctx context.Context, path string) (*api.Secret, err
 		c.incrementOperationCounter(metrics.OperationRead, err)
 	}()
 
+	var release func()
+	release, err = c.throttle(ctx, metrics.OperationRead)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	var secret *api.Secret
+	secret, err = c.client.Logical().ReadWithDataWithContext(ctx, path, data)
+	if err != nil {
+		return nil, err
+	}
+
+	if cgErr, ok := newControlGroupError(requestPath(path, data), secret); ok {
+		err = cgErr
+		return nil, err
+	}
+
+	return secret, nil
+}
+
+func (c *defaultClient) List(ctx context.Context, path string) (*api.Secret, error) {
+	var err error
+	startTS := time.Now()
+	defer func() {
+		c.observeTime(startTS, metrics.OperationList)
+		c.incrementOperationCounter(metrics.OperationList, err)
+	}()
+
+	var release func()
+	release, err = c.throttle(ctx, metrics.OperationList)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
 	var secret *api.Secret
-	secret, err = c.client.Logical().ReadWithContext(ctx, path)
+	secret, err = c.client.Logical().ListWithContext(ctx, path)
 	return secret, err
 }
 
@@ -475,9 +688,25 @@ func (c *defaultClient) Write(ctx context.Context, path string, m map[string]any
 		c.incrementOperationCounter(metrics.OperationWrite, err)
 	}()
 
+	var release func()
+	release, err = c.throttle(ctx, metrics.OperationWrite)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
 	var secret *api.Secret
 	secret, err = c.client.Logical().WriteWithContext(ctx, path, m)
-	return secret, err
+	if err != nil {
+		return nil, err
+	}
+
+	if cgErr, ok := newControlGroupError(path, secret); ok {
+		err = cgErr
+		return nil, err
+	}
+
+	return secret, nil
 }
 
 func (c *defaultClient) renew(ctx context.Context) error {
@@ -516,20 +745,34 @@ func (c *defaultClient) renew(ctx context.Context) error {
 
 func (c *defaultClient) init(ctx context.Context, client ctrlclient.Client, authObj *secretsv1alpha1.VaultAuth, connObj *secretsv1alpha1.VaultConnection, providerNamespace string, opts *ClientOptions) error {
 	cfg := &ClientConfig{
-		Address:         connObj.Spec.Address,
-		SkipTLSVerify:   connObj.Spec.SkipTLSVerify,
-		TLSServerName:   connObj.Spec.TLSServerName,
-		VaultNamespace:  authObj.Spec.Namespace,
-		CACertSecretRef: connObj.Spec.CACertSecretRef,
-		K8sNamespace:    providerNamespace,
+		Addresses:          ConnectionAddresses(connObj.Spec),
+		SkipTLSVerify:      connObj.Spec.SkipTLSVerify,
+		TLSServerName:      connObj.Spec.TLSServerName,
+		VaultNamespace:     authObj.Spec.Namespace,
+		CACertSecretRef:    connObj.Spec.CACertSecretRef,
+		CACertConfigMapRef: connObj.Spec.CACertConfigMapRef,
+		CACertConfigMapKey: connObj.Spec.CACertConfigMapKey,
+		K8sNamespace:       providerNamespace,
+		// the CA configmap is owned by the VaultConnection.
+		CACertConfigMapNamespace: connObj.Namespace,
+		// the client certificate is owned by the VaultConnection.
+		ClientCertSecretRef:       connObj.Spec.ClientCertSecretRef,
+		ClientCertSecretNamespace: connObj.Namespace,
+	}
+	if err := cfg.SetConnectionOptions(connObj); err != nil {
+		return err
 	}
 
-	vc, err := MakeVaultClient(ctx, cfg, client)
+	credentialProvider, err := credentials.NewCredentialProvider(ctx, client, authObj, providerNamespace)
 	if err != nil {
 		return err
 	}
 
-	credentialProvider, err := credentials.NewCredentialProvider(ctx, client, authObj, providerNamespace)
+	if p, ok := credentialProvider.(credentials.ClientCertificateProvider); ok {
+		cfg.GetClientCertificate = p.GetClientCertificate
+	}
+
+	vc, err := MakeVaultClient(ctx, cfg, client)
 	if err != nil {
 		return err
 	}
@@ -539,10 +782,27 @@ func (c *defaultClient) init(ctx context.Context, client ctrlclient.Client, auth
 	c.client = vc
 	c.authObj = authObj
 	c.connObj = connObj
+	c.limiter = connectionLimiters.get(connObj)
 
 	return nil
 }
 
+// throttle blocks until the VaultConnection's rate limit and max in-flight requests
+// allow a request to be sent to Vault. Upon success, the returned func must be called
+// once the request has completed.
+func (c *defaultClient) throttle(ctx context.Context, operation string) (func(), error) {
+	if c.limiter == nil {
+		return func() {}, nil
+	}
+
+	startTS := time.Now()
+	release, err := c.limiter.wait(ctx)
+	clientThrottleTimes.WithLabelValues(operation, ctrlclient.ObjectKeyFromObject(c.connObj).String()).Observe(
+		time.Since(startTS).Seconds(),
+	)
+	return release, err
+}
+
 func (c *defaultClient) observeTime(ts time.Time, operation string) {
 	clientOperationTimes.WithLabelValues(operation, ctrlclient.ObjectKeyFromObject(c.connObj).String()).Observe(
 		time.Since(ts).Seconds(),
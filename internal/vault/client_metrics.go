@@ -28,6 +28,17 @@ var (
 		Help: "Length of time per Vault client operation",
 	}, []string{metrics.LabelOperation, metrics.LabelVaultConnection})
 
+	clientThrottleTimes = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: subsystemClient,
+		Name:      metrics.NameThrottleTimeSeconds,
+		Buckets: []float64{
+			0.005, 0.01, 0.025, 0.05, 0.1, 0.15, 0.2, 0.25, 0.3, 0.35, 0.4, 0.45, 0.5, 0.6, 0.7, 0.8, 0.9, 1.0,
+			1.25, 1.5, 1.75, 2.0, 2.5, 3.0, 3.5, 4.0, 4.5, 5, 6, 7, 8, 9, 10,
+		},
+		Help: "Length of time per Vault client operation spent waiting on the VaultConnection's rate limit and max in-flight requests",
+	}, []string{metrics.LabelOperation, metrics.LabelVaultConnection})
+
 	clientOperations = prometheus.NewCounterVec(prometheus.CounterOpts{
 		Namespace:   metrics.Namespace,
 		Subsystem:   subsystemClient,
@@ -49,6 +60,7 @@ var (
 func MustRegisterClientMetrics(registry prometheus.Registerer) {
 	registry.MustRegister(
 		clientOperationTimes,
+		clientThrottleTimes,
 		clientOperations,
 		clientOperationErrors,
 	)
@@ -4,16 +4,29 @@
 package vault
 
 import (
+	"context"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/hashicorp/vault/api"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	secretsv1alpha1 "github.com/hashicorp/vault-secrets-operator/api/v1alpha1"
+	"github.com/hashicorp/vault-secrets-operator/internal/vault/credentials"
 )
 
 func Test_defaultClient_CheckExpiry(t *testing.T) {
 	type fields struct {
+		authObj     *secretsv1alpha1.VaultAuth
 		lastResp    *api.Secret
 		lastRenewal int64
 	}
@@ -99,6 +112,69 @@ func Test_defaultClient_CheckExpiry(t *testing.T) {
 			want:    true,
 			wantErr: assert.NoError,
 		},
+		{
+			name: "valid-token-method-zero-ttl",
+			fields: fields{
+				authObj: &secretsv1alpha1.VaultAuth{
+					Spec: secretsv1alpha1.VaultAuthSpec{
+						Method: credentials.ProviderMethodToken,
+					},
+				},
+				lastResp: &api.Secret{
+					Auth: &api.SecretAuth{
+						LeaseDuration: 0,
+					},
+				},
+				lastRenewal: time.Now().Unix() - 3600,
+			},
+			args: args{
+				offset: 5,
+			},
+			want:    false,
+			wantErr: assert.NoError,
+		},
+		{
+			name: "expired-token-method-with-ttl",
+			fields: fields{
+				authObj: &secretsv1alpha1.VaultAuth{
+					Spec: secretsv1alpha1.VaultAuthSpec{
+						Method: credentials.ProviderMethodToken,
+					},
+				},
+				lastResp: &api.Secret{
+					Auth: &api.SecretAuth{
+						LeaseDuration: 30,
+					},
+				},
+				lastRenewal: time.Now().Unix() - 30,
+			},
+			args: args{
+				offset: 0,
+			},
+			want:    true,
+			wantErr: assert.NoError,
+		},
+		{
+			name: "expired-zero-ttl",
+			fields: fields{
+				authObj: &secretsv1alpha1.VaultAuth{
+					Spec: secretsv1alpha1.VaultAuthSpec{
+						Method: credentials.ProviderMethodKubernetes,
+					},
+				},
+				lastResp: &api.Secret{
+					Auth: &api.SecretAuth{
+						LeaseDuration: 0,
+					},
+				},
+				lastRenewal: time.Now().Unix(),
+			},
+			args: args{
+				offset: 0,
+			},
+			want:    true,
+			wantErr: assert.NoError,
+		},
 		{
 			fields: fields{},
 			want:   false,
@@ -145,6 +221,7 @@ func Test_defaultClient_CheckExpiry(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			c := &defaultClient{
+				authObj:     tt.fields.authObj,
 				authSecret:  tt.fields.lastResp,
 				lastRenewal: tt.fields.lastRenewal,
 			}
@@ -156,3 +233,329 @@ func Test_defaultClient_CheckExpiry(t *testing.T) {
 		})
 	}
 }
+
+func Test_defaultClient_lookupSelf(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/auth/token/lookup-self" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		switch r.Header.Get("X-Vault-Token") {
+		case "renewable-token":
+			fmt.Fprint(w, `{"request_id":"req-1","data":{"accessor":"accessor-1","policies":["default","dev"],`+
+				`"renewable":true,"ttl":3600,"entity_id":"entity-1"}}`)
+		case "root-token":
+			fmt.Fprint(w, `{"request_id":"req-2","data":{"accessor":"accessor-2","policies":["root"],`+
+				`"renewable":false,"ttl":0}}`)
+		default:
+			w.WriteHeader(http.StatusForbidden)
+			fmt.Fprint(w, `{"errors":["permission denied"]}`)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	tests := []struct {
+		name    string
+		creds   map[string]any
+		want    *api.Secret
+		wantErr assert.ErrorAssertionFunc
+	}{
+		{
+			name: "renewable",
+			creds: map[string]any{
+				credentials.TokenCredsKey: "renewable-token",
+			},
+			want: &api.Secret{
+				RequestID: "req-1",
+				Auth: &api.SecretAuth{
+					ClientToken:   "renewable-token",
+					Accessor:      "accessor-1",
+					Policies:      []string{"default", "dev"},
+					TokenPolicies: []string{"default", "dev"},
+					Renewable:     true,
+					LeaseDuration: 3600,
+					EntityID:      "entity-1",
+				},
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name: "non-renewable",
+			creds: map[string]any{
+				credentials.TokenCredsKey: "root-token",
+			},
+			want: &api.Secret{
+				RequestID: "req-2",
+				Auth: &api.SecretAuth{
+					ClientToken:   "root-token",
+					Accessor:      "accessor-2",
+					Policies:      []string{"root"},
+					TokenPolicies: []string{"root"},
+					Renewable:     false,
+					LeaseDuration: 0,
+				},
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name: "invalid-token",
+			creds: map[string]any{
+				credentials.TokenCredsKey: "invalid",
+			},
+			wantErr: assert.Error,
+		},
+		{
+			name:    "no-token",
+			creds:   map[string]any{},
+			wantErr: assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := api.DefaultConfig()
+			config.Address = server.URL
+			config.MaxRetries = 0
+			vc, err := api.NewClient(config)
+			require.NoError(t, err)
+			vc.ClearToken()
+
+			c := &defaultClient{
+				client:  vc,
+				connObj: &secretsv1alpha1.VaultConnection{},
+			}
+			got, err := c.lookupSelf(context.Background(), tt.creds)
+			if !tt.wantErr(t, err, fmt.Sprintf("lookupSelf(%v)", tt.creds)) {
+				return
+			}
+			assert.Equalf(t, tt.want, got, "lookupSelf(%v)", tt.creds)
+		})
+	}
+}
+
+func Test_defaultClient_Login_autoAuth(t *testing.T) {
+	config := api.DefaultConfig()
+	config.Address = "http://127.0.0.1:8200"
+	vc, err := api.NewClient(config)
+	require.NoError(t, err)
+	vc.SetToken("stale-token")
+
+	c := &defaultClient{
+		client: vc,
+		authObj: &secretsv1alpha1.VaultAuth{
+			Spec: secretsv1alpha1.VaultAuthSpec{
+				Method: credentials.ProviderMethodAutoAuth,
+			},
+		},
+		connObj:            &secretsv1alpha1.VaultConnection{},
+		credentialProvider: &credentials.AutoAuthCredentialProvider{},
+	}
+
+	// no requests are sent to Vault, the proxy injects its own token.
+	require.NoError(t, c.Login(context.Background(), nil))
+	assert.Empty(t, vc.Token())
+	assert.NotNil(t, c.GetTokenSecret())
+
+	expired, err := c.CheckExpiry(0)
+	require.NoError(t, err)
+	assert.False(t, expired)
+}
+
+// staticCredentialProvider is a credentials.CredentialProvider that always returns creds.
+type staticCredentialProvider struct {
+	credentials.CredentialProvider
+	creds map[string]any
+}
+
+func (p *staticCredentialProvider) GetCreds(context.Context, ctrlclient.Client) (map[string]any, error) {
+	creds := make(map[string]any, len(p.creds))
+	for k, v := range p.creds {
+		creds[k] = v
+	}
+	return creds, nil
+}
+
+func Test_defaultClient_Login_headers(t *testing.T) {
+	var mu sync.Mutex
+	requestHeaders := make(map[string]http.Header)
+	getHeaders := func(path string) http.Header {
+		mu.Lock()
+		defer mu.Unlock()
+		return requestHeaders[path]
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requestHeaders[r.URL.Path] = r.Header.Clone()
+		mu.Unlock()
+		switch r.URL.Path {
+		case "/v1/auth/approle/login":
+			fmt.Fprint(w, `{"auth":{"client_token":"client-token","renewable":false,"lease_duration":3600}}`)
+		case "/v1/kv/data/app":
+			fmt.Fprint(w, `{"data":{"data":{"username":"user"}}}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	headersSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "gateway",
+			Namespace: "tenant-1",
+		},
+		Data: map[string][]byte{
+			"X-Tenant": []byte("tenant-1"),
+		},
+	}
+
+	tests := []struct {
+		name        string
+		headersFrom []secretsv1alpha1.SecretKeySelector
+		wantLogin   map[string]string
+		wantErr     assert.ErrorAssertionFunc
+	}{
+		{
+			name: "headers",
+			wantLogin: map[string]string{
+				"X-Role":   "app",
+				"X-Tenant": "tenant-0",
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name: "headers-from",
+			headersFrom: []secretsv1alpha1.SecretKeySelector{
+				{Name: "gateway", Key: "X-Tenant"},
+			},
+			wantLogin: map[string]string{
+				"X-Role":   "app",
+				"X-Tenant": "tenant-1",
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name: "headers-from-missing-key",
+			headersFrom: []secretsv1alpha1.SecretKeySelector{
+				{Name: "gateway", Key: "X-Other"},
+			},
+			wantErr: assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mu.Lock()
+			requestHeaders = make(map[string]http.Header)
+			mu.Unlock()
+
+			config := api.DefaultConfig()
+			config.Address = server.URL
+			config.MaxRetries = 0
+			vc, err := api.NewClient(config)
+			require.NoError(t, err)
+			vc.ClearToken()
+			// the VaultConnection's headers are included in all requests.
+			vc.AddHeader("X-Connection", "conn-1")
+
+			c := &defaultClient{
+				client: vc,
+				authObj: &secretsv1alpha1.VaultAuth{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "default",
+						Namespace: "tenant-1",
+					},
+					Spec: secretsv1alpha1.VaultAuthSpec{
+						Method: credentials.ProviderMethodAppRole,
+						Mount:  "approle",
+						Headers: map[string]string{
+							"X-Role":   "app",
+							"X-Tenant": "tenant-0",
+						},
+						HeadersFrom: tt.headersFrom,
+					},
+				},
+				connObj: &secretsv1alpha1.VaultConnection{},
+				credentialProvider: &staticCredentialProvider{
+					creds: map[string]any{"role_id": "role-1", "secret_id": "secret-1"},
+				},
+			}
+
+			ctx := context.Background()
+			err = c.Login(ctx, fake.NewClientBuilder().WithObjects(headersSecret).Build())
+			if !tt.wantErr(t, err, "Login()") || err != nil {
+				return
+			}
+
+			login := getHeaders("/v1/auth/approle/login")
+			require.NotNil(t, login)
+			assert.Equal(t, "conn-1", login.Get("X-Connection"))
+			for k, v := range tt.wantLogin {
+				assert.Equal(t, v, login.Get(k), "login header %s", k)
+			}
+
+			// the VaultAuth's headers are only included in the login request.
+			_, err = c.Read(ctx, "kv/data/app")
+			require.NoError(t, err)
+			read := getHeaders("/v1/kv/data/app")
+			require.NotNil(t, read)
+			assert.Equal(t, "conn-1", read.Get("X-Connection"))
+			for k := range tt.wantLogin {
+				assert.Empty(t, read.Get(k), "read header %s", k)
+			}
+		})
+	}
+}
+
+func Test_loginPath(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		creds  map[string]any
+		want   string
+	}{
+		{
+			name:   "kubernetes",
+			method: credentials.ProviderMethodKubernetes,
+			creds: map[string]any{
+				"role": "role-1",
+			},
+			want: "auth/baz/login",
+		},
+		{
+			name:   "userpass",
+			method: credentials.ProviderMethodUserPass,
+			creds: map[string]any{
+				credentials.UsernameCredsKey: "alice",
+				"password":                   "secret",
+			},
+			want: "auth/baz/login/alice",
+		},
+		{
+			name:   "ldap",
+			method: credentials.ProviderMethodLDAP,
+			creds: map[string]any{
+				credentials.UsernameCredsKey: "bob",
+				"password":                   "secret",
+			},
+			want: "auth/baz/login/bob",
+		},
+		{
+			name:   "userpass-escaped",
+			method: credentials.ProviderMethodUserPass,
+			creds: map[string]any{
+				credentials.UsernameCredsKey: "../../sys/alice?x",
+				"password":                   "secret",
+			},
+			want: "auth/baz/login/..%2F..%2Fsys%2Falice%3Fx",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			authObj := &secretsv1alpha1.VaultAuth{
+				Spec: secretsv1alpha1.VaultAuthSpec{
+					Method: tt.method,
+					Mount:  "baz",
+				},
+			}
+			assert.Equalf(t, tt.want, loginPath(authObj, tt.creds), "loginPath(%v, %v)", authObj, tt.creds)
+		})
+	}
+}
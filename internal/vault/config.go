@@ -4,14 +4,37 @@
 package vault
 
 import (
+	"bytes"
 	"context"
+	"crypto/tls"
 	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
 
 	"github.com/hashicorp/vault/api"
+	"golang.org/x/net/http/httpproxy"
 	"k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
 	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	secretsv1alpha1 "github.com/hashicorp/vault-secrets-operator/api/v1alpha1"
+)
+
+const unixSocketScheme = "unix://"
+
+const (
+	// ReadConsistencyEventual allows performance standbys to serve possibly stale reads.
+	ReadConsistencyEventual = "eventual"
+	// ReadConsistencyReadYourWrites requires that performance standbys have
+	// caught up to the client's last write, 412 responses are retried.
+	ReadConsistencyReadYourWrites = "readYourWrites"
+	// ReadConsistencyForwardInconsistent is ReadConsistencyReadYourWrites, but
+	// performance standbys forward the request to the active node instead of
+	// responding with a 412.
+	ReadConsistencyForwardInconsistent = "forwardInconsistent"
 )
 
 // ClientConfig contains the connection and auth information to construct a
@@ -21,10 +44,24 @@ type ClientConfig struct {
 	// "ca.crt" that holds a CA cert that can be used to validate the
 	// certificate presented by the Vault server
 	CACertSecretRef string
+	// CACertConfigMapRef is the name of a k8s configmap that holds a CA cert
+	// that can be used to validate the certificate presented by the Vault
+	// server, it is trusted in addition to the CACertSecretRef CA cert
+	CACertConfigMapRef string
+	// CACertConfigMapKey is the data key of the CA cert in the
+	// CACertConfigMapRef configmap, defaults to "ca.crt"
+	CACertConfigMapKey string
+	// CACertConfigMapNamespace the namespace of the CACertConfigMapRef
+	// configmap, defaults to K8sNamespace
+	CACertConfigMapNamespace string
 	// K8sNamespace the namespace of the CACertSecretRef secret
 	K8sNamespace string
-	// Address is the URL of the Vault server
+	// Address is the URL of the Vault server, a unix:// address is the path
+	// of the unix socket of a Vault Agent or Vault Proxy
 	Address string
+	// Addresses are the URLs of the Vault servers in order of preference,
+	// the client fails over between them. Address is ignored when set.
+	Addresses []string
 	// SkipTLSVerify controls whether the Vault server's TLS certificate is
 	// verified
 	SkipTLSVerify bool
@@ -33,6 +70,86 @@ type ClientConfig struct {
 	TLSServerName string
 	// VaultNamespace is the namespace in Vault to auth to
 	VaultNamespace string
+	// ClientCertSecretRef is the name of a kubernetes.io/tls secret that holds
+	// the client certificate and private key presented to the Vault server
+	ClientCertSecretRef string
+	// ClientCertSecretNamespace the namespace of the ClientCertSecretRef secret,
+	// defaults to K8sNamespace
+	ClientCertSecretNamespace string
+	// GetClientCertificate, if set, is called during the TLS handshake to
+	// obtain the client certificate that is presented to the Vault server.
+	// It is required for authenticating via Vault's cert auth method.
+	GetClientCertificate func(*tls.CertificateRequestInfo) (*tls.Certificate, error)
+	// ProxyURL is the URL of the HTTP proxy that all requests are sent through,
+	// it takes precedence over the proxy environment variables.
+	ProxyURL string
+	// NoProxy are the hosts that bypass ProxyURL, in the NO_PROXY format
+	NoProxy []string
+	// ProxySecretRef is the name of a kubernetes.io/basic-auth secret that
+	// holds the credentials used to authenticate to ProxyURL
+	ProxySecretRef string
+	// ProxySecretNamespace the namespace of the ProxySecretRef secret,
+	// defaults to K8sNamespace
+	ProxySecretNamespace string
+	// Timeout of every request, the Vault client's default is used when zero
+	Timeout time.Duration
+	// MaxRetries of failed requests, the Vault client's default is used when nil
+	MaxRetries *int
+	// MinRetryWait is the minimum time to wait before retrying a request,
+	// the Vault client's default is used when zero
+	MinRetryWait time.Duration
+	// MaxRetryWait is the maximum time to wait before retrying a request,
+	// the Vault client's default is used when zero
+	MaxRetryWait time.Duration
+	// Headers are included in all requests
+	Headers map[string]string
+	// HeadersFrom are secret keys whose values are included as headers in
+	// all requests, the key is used as the header name
+	HeadersFrom []secretsv1alpha1.SecretKeySelector
+	// HeadersFromNamespace the namespace of the HeadersFrom secrets,
+	// defaults to K8sNamespace
+	HeadersFromNamespace string
+	// ReadConsistency of requests sent to performance standbys, one of
+	// ReadConsistencyEventual, ReadConsistencyReadYourWrites, or
+	// ReadConsistencyForwardInconsistent, defaults to ReadConsistencyEventual
+	ReadConsistency string
+}
+
+// SetConnectionOptions sets the proxy, timeout, and retry options from the
+// VaultConnection's spec.
+func (c *ClientConfig) SetConnectionOptions(connObj *secretsv1alpha1.VaultConnection) error {
+	c.ProxyURL = connObj.Spec.ProxyURL
+	c.NoProxy = connObj.Spec.NoProxy
+	c.ProxySecretRef = connObj.Spec.ProxySecretRef
+	// the proxy credentials are owned by the VaultConnection.
+	c.ProxySecretNamespace = connObj.Namespace
+	c.MaxRetries = connObj.Spec.MaxRetries
+	c.ReadConsistency = connObj.Spec.ReadConsistency
+	c.Headers = connObj.Spec.Headers
+	c.HeadersFrom = connObj.Spec.HeadersFrom
+	// the header secrets are owned by the VaultConnection.
+	c.HeadersFromNamespace = connObj.Namespace
+
+	for _, d := range []struct {
+		name  string
+		value string
+		dest  *time.Duration
+	}{
+		{"timeout", connObj.Spec.Timeout, &c.Timeout},
+		{"minRetryWait", connObj.Spec.MinRetryWait, &c.MinRetryWait},
+		{"maxRetryWait", connObj.Spec.MaxRetryWait, &c.MaxRetryWait},
+	} {
+		if d.value == "" {
+			continue
+		}
+		v, err := time.ParseDuration(d.value)
+		if err != nil {
+			return fmt.Errorf("invalid %s %q: %w", d.name, d.value, err)
+		}
+		*d.dest = v
+	}
+
+	return nil
 }
 
 // MakeVaultClient creates a Vault api.Client from a ClientConfig.
@@ -57,9 +174,22 @@ func MakeVaultClient(ctx context.Context, cfg *ClientConfig, client ctrlclient.C
 		}
 	}
 
+	if cfg.CACertConfigMapRef != "" {
+		caCert, err := getCACertFromConfigMap(ctx, client, cfg)
+		if err != nil {
+			return nil, err
+		}
+		// both CA certs are combined into a single pool.
+		b = bytes.Join([][]byte{b, caCert}, []byte("\n"))
+	}
+
 	config := api.DefaultConfig()
 
-	config.Address = cfg.Address
+	addresses := cfg.Addresses
+	if len(addresses) == 0 {
+		addresses = []string{cfg.Address}
+	}
+	config.Address = addresses[0]
 	if err := config.ConfigureTLS(&api.TLSConfig{
 		Insecure:      cfg.SkipTLSVerify,
 		TLSServerName: cfg.TLSServerName,
@@ -68,6 +198,96 @@ func MakeVaultClient(ctx context.Context, cfg *ClientConfig, client ctrlclient.C
 		return nil, err
 	}
 
+	if cfg.Timeout != 0 {
+		config.Timeout = cfg.Timeout
+	}
+	if cfg.MaxRetries != nil {
+		config.MaxRetries = *cfg.MaxRetries
+	}
+	if cfg.MinRetryWait != 0 {
+		config.MinRetryWait = cfg.MinRetryWait
+	}
+	if cfg.MaxRetryWait != 0 {
+		config.MaxRetryWait = cfg.MaxRetryWait
+	}
+
+	if isUnixSocketAddress(addresses...) {
+		if len(addresses) > 1 {
+			return nil, fmt.Errorf("unix socket addresses cannot be failed over")
+		}
+		if cfg.ProxyURL != "" {
+			return nil, fmt.Errorf("a proxy URL cannot be used with a unix socket address")
+		}
+
+		transport, ok := config.HttpClient.Transport.(*http.Transport)
+		if !ok {
+			return nil, fmt.Errorf("unsupported HTTP transport %T, cannot configure the unix socket",
+				config.HttpClient.Transport)
+		}
+		// requests are always dialed to the socket, the Vault client
+		// configures the transport's dialer from the unix:// address.
+		transport.Proxy = nil
+	}
+
+	if cfg.ProxyURL == "" && (len(cfg.NoProxy) > 0 || cfg.ProxySecretRef != "") {
+		return nil, fmt.Errorf("a proxy URL is required when setting the no-proxy list or proxy credentials")
+	}
+
+	if cfg.ProxyURL != "" {
+		transport, ok := config.HttpClient.Transport.(*http.Transport)
+		if !ok {
+			return nil, fmt.Errorf("unsupported HTTP transport %T, cannot configure the proxy",
+				config.HttpClient.Transport)
+		}
+
+		proxy, err := getProxyFunc(ctx, client, cfg)
+		if err != nil {
+			return nil, err
+		}
+		transport.Proxy = proxy
+	}
+
+	if cfg.GetClientCertificate != nil || cfg.ClientCertSecretRef != "" {
+		transport, ok := config.HttpClient.Transport.(*http.Transport)
+		if !ok {
+			return nil, fmt.Errorf("unsupported HTTP transport %T, cannot configure the client certificate",
+				config.HttpClient.Transport)
+		}
+
+		if cfg.GetClientCertificate != nil {
+			// takes precedence over ClientCertSecretRef during the TLS handshake.
+			transport.TLSClientConfig.GetClientCertificate = cfg.GetClientCertificate
+		}
+
+		if cfg.ClientCertSecretRef != "" {
+			cert, err := getClientCertificate(ctx, client, cfg)
+			if err != nil {
+				return nil, err
+			}
+			transport.TLSClientConfig.Certificates = []tls.Certificate{*cert}
+		}
+	}
+
+	if len(addresses) > 1 {
+		transport, err := newFailoverTransport(config.HttpClient.Transport, addresses)
+		if err != nil {
+			return nil, err
+		}
+		// reads are spread across the performance standbys.
+		transport.perfStandbyOK = cfg.ReadConsistency != ""
+		config.HttpClient.Transport = transport
+	}
+
+	switch cfg.ReadConsistency {
+	case "", ReadConsistencyEventual:
+	case ReadConsistencyReadYourWrites, ReadConsistencyForwardInconsistent:
+		// the replication state is shared with all clones, the Vault client's
+		// default retry policy retries 412 responses.
+		config.ReadYourWrites = true
+	default:
+		return nil, fmt.Errorf("unsupported read consistency %q", cfg.ReadConsistency)
+	}
+
 	config.CloneToken = true
 	config.CloneHeaders = true
 
@@ -80,5 +300,179 @@ func MakeVaultClient(ctx context.Context, cfg *ClientConfig, client ctrlclient.C
 		c.SetNamespace(cfg.VaultNamespace)
 	}
 
+	if cfg.ReadConsistency == ReadConsistencyForwardInconsistent {
+		headers := c.Headers()
+		headers.Set(api.HeaderInconsistent, "forward-active-node")
+		c.SetHeaders(headers)
+	}
+
+	if len(cfg.Headers) > 0 || len(cfg.HeadersFrom) > 0 {
+		namespace := cfg.HeadersFromNamespace
+		if namespace == "" {
+			namespace = cfg.K8sNamespace
+		}
+		headersFrom, err := getHeadersFromSecrets(ctx, client, namespace, cfg.HeadersFrom)
+		if err != nil {
+			return nil, err
+		}
+
+		headers := c.Headers()
+		for _, h := range []map[string]string{cfg.Headers, headersFrom} {
+			for k, v := range h {
+				headers.Set(k, v)
+			}
+		}
+		c.SetHeaders(headers)
+	}
+
 	return c, nil
 }
+
+// getHeadersFromSecrets returns the request headers for the selected secret keys,
+// the key is used as the header name.
+func getHeadersFromSecrets(ctx context.Context, client ctrlclient.Client, namespace string, refs []secretsv1alpha1.SecretKeySelector) (map[string]string, error) {
+	headers := make(map[string]string, len(refs))
+	secrets := make(map[string]*v1.Secret)
+	for _, ref := range refs {
+		s, ok := secrets[ref.Name]
+		if !ok {
+			s = &v1.Secret{}
+			if err := client.Get(ctx, types.NamespacedName{
+				Namespace: namespace,
+				Name:      ref.Name,
+			}, s); err != nil {
+				return nil, err
+			}
+			secrets[ref.Name] = s
+		}
+
+		v := strings.TrimSpace(string(s.Data[ref.Key]))
+		if v == "" {
+			return nil, fmt.Errorf("no data found for key %q in secret %s/%s", ref.Key, namespace, ref.Name)
+		}
+		headers[ref.Key] = v
+	}
+
+	return headers, nil
+}
+
+// isUnixSocketAddress returns true if any of the addresses is a unix:// socket path,
+// e.g. the listener of a Vault Agent or Vault Proxy sidecar.
+func isUnixSocketAddress(addresses ...string) bool {
+	for _, addr := range addresses {
+		if strings.HasPrefix(addr, unixSocketScheme) {
+			return true
+		}
+	}
+	return false
+}
+
+// getClientCertificate loads the client certificate from the ClientConfig's
+// kubernetes.io/tls ClientCertSecretRef secret.
+func getClientCertificate(ctx context.Context, client ctrlclient.Client, cfg *ClientConfig) (*tls.Certificate, error) {
+	namespace := cfg.ClientCertSecretNamespace
+	if namespace == "" {
+		namespace = cfg.K8sNamespace
+	}
+
+	s := &v1.Secret{}
+	if err := client.Get(ctx, types.NamespacedName{
+		Namespace: namespace,
+		Name:      cfg.ClientCertSecretRef,
+	}, s); err != nil {
+		return nil, err
+	}
+
+	if s.Type != v1.SecretTypeTLS {
+		return nil, fmt.Errorf("client certificate secret %s/%s must be of type %q, found %q",
+			namespace, cfg.ClientCertSecretRef, v1.SecretTypeTLS, s.Type)
+	}
+
+	cert, err := tls.X509KeyPair(s.Data[v1.TLSCertKey], s.Data[v1.TLSPrivateKeyKey])
+	if err != nil {
+		return nil, fmt.Errorf("invalid client certificate in secret %s/%s: %w",
+			namespace, cfg.ClientCertSecretRef, err)
+	}
+
+	return &cert, nil
+}
+
+// getCACertFromConfigMap loads the PEM encoded CA cert from the ClientConfig's
+// CACertConfigMapRef configmap.
+func getCACertFromConfigMap(ctx context.Context, client ctrlclient.Client, cfg *ClientConfig) ([]byte, error) {
+	key := cfg.CACertConfigMapKey
+	if key == "" {
+		key = "ca.crt"
+	}
+	namespace := cfg.CACertConfigMapNamespace
+	if namespace == "" {
+		namespace = cfg.K8sNamespace
+	}
+
+	cm := &v1.ConfigMap{}
+	if err := client.Get(ctx, types.NamespacedName{
+		Namespace: namespace,
+		Name:      cfg.CACertConfigMapRef,
+	}, cm); err != nil {
+		return nil, err
+	}
+
+	caCert, ok := cm.Data[key]
+	if !ok || caCert == "" {
+		return nil, fmt.Errorf("%q was empty in the CA configmap %s/%s", key, namespace, cfg.CACertConfigMapRef)
+	}
+
+	return []byte(caCert), nil
+}
+
+// getProxyFunc returns the function that selects the proxy for a request,
+// based on the ClientConfig's ProxyURL and NoProxy list. The credentials from
+// the ProxySecretRef secret are added to the proxy URL, so that they are sent
+// in the Proxy-Authorization header.
+func getProxyFunc(ctx context.Context, client ctrlclient.Client, cfg *ClientConfig) (func(*http.Request) (*url.URL, error), error) {
+	u, err := url.Parse(cfg.ProxyURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy URL %q: %w", cfg.ProxyURL, err)
+	}
+	if u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("invalid proxy URL %q: scheme and host are required", cfg.ProxyURL)
+	}
+
+	if cfg.ProxySecretRef != "" {
+		namespace := cfg.ProxySecretNamespace
+		if namespace == "" {
+			namespace = cfg.K8sNamespace
+		}
+
+		s := &v1.Secret{}
+		if err := client.Get(ctx, types.NamespacedName{
+			Namespace: namespace,
+			Name:      cfg.ProxySecretRef,
+		}, s); err != nil {
+			return nil, err
+		}
+
+		if s.Type != v1.SecretTypeBasicAuth {
+			return nil, fmt.Errorf("proxy secret %s/%s must be of type %q, found %q",
+				namespace, cfg.ProxySecretRef, v1.SecretTypeBasicAuth, s.Type)
+		}
+
+		username := string(s.Data[v1.BasicAuthUsernameKey])
+		if username == "" {
+			return nil, fmt.Errorf("%q was empty in the proxy secret %s/%s",
+				v1.BasicAuthUsernameKey, namespace, cfg.ProxySecretRef)
+		}
+		u.User = url.UserPassword(username, string(s.Data[v1.BasicAuthPasswordKey]))
+	}
+
+	proxyConfig := &httpproxy.Config{
+		HTTPProxy:  u.String(),
+		HTTPSProxy: u.String(),
+		NoProxy:    strings.Join(cfg.NoProxy, ","),
+	}
+	proxyFunc := proxyConfig.ProxyFunc()
+
+	return func(req *http.Request) (*url.URL, error) {
+		return proxyFunc(req.URL)
+	}, nil
+}
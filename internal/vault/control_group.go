@@ -0,0 +1,185 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package vault
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	lru "github.com/hashicorp/golang-lru"
+	"github.com/hashicorp/vault/api"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// ErrControlGroupRequestNotFound is returned when the wrapping token of a control group
+// request is unknown, e.g. after the operator has restarted. A new request must be made.
+var ErrControlGroupRequestNotFound = errors.New("control group request not found")
+
+// controlGroupRequests tracks the wrapping tokens of all pending control group requests,
+// keyed by the UID of the requesting resource and the wrapping token's accessor. The wrapping
+// tokens are never persisted, only their accessors are recorded in the status of the requesting
+// resource. Including the UID in the key ensures that a resource can only ever unwrap the
+// responses of its own requests, even if its status is set to another resource's accessor.
+var controlGroupRequests, _ = lru.New(1000)
+
+// controlGroupRequest is a pending control group request.
+type controlGroupRequest struct {
+	// token is the response-wrapping token.
+	token string
+	// path of the request, see requestPath.
+	path string
+}
+
+func controlGroupRequestKey(uid types.UID, accessor string) string {
+	return fmt.Sprintf("%s/%s", uid, accessor)
+}
+
+// ControlGroupError is returned when a request is held by a Vault Enterprise control group,
+// Vault returns a wrapping token for the response which can only be unwrapped once the
+// request has been authorized.
+type ControlGroupError struct {
+	// Accessor of the response-wrapping token.
+	Accessor string
+	// Path of the request, including any query parameters.
+	Path string
+	// CreationTime of the response-wrapping token.
+	CreationTime time.Time
+	// TTL of the response-wrapping token.
+	TTL time.Duration
+	// token is the response-wrapping token, it is never exposed.
+	token string
+}
+
+func (e *ControlGroupError) Error() string {
+	return fmt.Sprintf("request to %s requires control group authorization, accessor=%s", e.Path, e.Accessor)
+}
+
+// newControlGroupError returns a ControlGroupError if resp is a control group's
+// response-wrapping token.
+func newControlGroupError(path string, resp *api.Secret) (*ControlGroupError, bool) {
+	// VSO never requests response-wrapping, so a wrapped response without any data
+	// can only come from a control group.
+	if resp == nil || resp.WrapInfo == nil || resp.Data != nil {
+		return nil, false
+	}
+
+	return &ControlGroupError{
+		Accessor:     resp.WrapInfo.Accessor,
+		Path:         path,
+		CreationTime: resp.WrapInfo.CreationTime,
+		TTL:          time.Duration(resp.WrapInfo.TTL) * time.Second,
+		token:        resp.WrapInfo.Token,
+	}, true
+}
+
+// TrackControlGroupRequest tracks the wrapping token of err for the resource identified
+// by uid, so that the response can be unwrapped by ReadControlGroup once the request has
+// been authorized.
+func TrackControlGroupRequest(uid types.UID, err *ControlGroupError) {
+	if err.token == "" {
+		return
+	}
+	controlGroupRequests.Add(controlGroupRequestKey(uid, err.Accessor), &controlGroupRequest{
+		token: err.token,
+		path:  err.Path,
+	})
+}
+
+// requestPath returns the path of a request with its query parameters data.
+func requestPath(path string, data map[string][]string) string {
+	if len(data) == 0 {
+		return path
+	}
+	return path + "?" + url.Values(data).Encode()
+}
+
+// ReadControlGroup returns the response of the control group request identified by accessor,
+// that was made by the resource identified by uid, along with the path of the original request.
+// A ControlGroupError is returned while the request is awaiting authorization.
+func (c *defaultClient) ReadControlGroup(ctx context.Context, uid types.UID, accessor string) (*api.Secret, string, error) {
+	key := controlGroupRequestKey(uid, accessor)
+	v, ok := controlGroupRequests.Get(key)
+	if !ok {
+		return nil, "", ErrControlGroupRequestNotFound
+	}
+	req := v.(*controlGroupRequest)
+
+	resp, err := c.Write(ctx, "sys/control-group/request", map[string]any{
+		"accessor": accessor,
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	if resp == nil || resp.Data == nil {
+		return nil, "", fmt.Errorf("empty response from control group request %s", accessor)
+	}
+
+	if approved, _ := resp.Data["approved"].(bool); !approved {
+		return nil, "", &ControlGroupError{
+			Accessor: accessor,
+			Path:     req.path,
+			token:    req.token,
+		}
+	}
+
+	secret, err := c.unwrap(ctx, req.token)
+	if err != nil {
+		return nil, "", err
+	}
+	// a wrapping token can only ever be unwrapped once.
+	controlGroupRequests.Remove(key)
+
+	return secret, req.path, nil
+}
+
+// WithControlGroupResponse returns a Client that responds to the first request made to path
+// with resp, the authorized response of a control group request that was made to the same path.
+// All other requests are made by c. This allows a control group request to be replayed
+// without having to special case its response.
+func WithControlGroupResponse(c Client, path string, resp *api.Secret) Client {
+	return &controlGroupResponseClient{
+		Client: c,
+		path:   path,
+		resp:   resp,
+	}
+}
+
+type controlGroupResponseClient struct {
+	Client
+	path string
+	resp *api.Secret
+}
+
+func (c *controlGroupResponseClient) Read(ctx context.Context, path string) (*api.Secret, error) {
+	if resp, ok := c.take(path); ok {
+		return resp, nil
+	}
+	return c.Client.Read(ctx, path)
+}
+
+func (c *controlGroupResponseClient) ReadWithData(ctx context.Context, path string, data map[string][]string) (*api.Secret, error) {
+	if resp, ok := c.take(requestPath(path, data)); ok {
+		return resp, nil
+	}
+	return c.Client.ReadWithData(ctx, path, data)
+}
+
+func (c *controlGroupResponseClient) Write(ctx context.Context, path string, m map[string]any) (*api.Secret, error) {
+	if resp, ok := c.take(path); ok {
+		return resp, nil
+	}
+	return c.Client.Write(ctx, path, m)
+}
+
+func (c *controlGroupResponseClient) take(path string) (*api.Secret, bool) {
+	if c.resp == nil || c.path != path {
+		return nil, false
+	}
+	resp := c.resp
+	c.resp = nil
+	return resp, true
+}
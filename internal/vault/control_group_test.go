@@ -0,0 +1,191 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package vault
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/vault/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/types"
+
+	secretsv1alpha1 "github.com/hashicorp/vault-secrets-operator/api/v1alpha1"
+)
+
+func Test_defaultClient_ReadControlGroup(t *testing.T) {
+	var mu sync.Mutex
+	var approved bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch r.URL.Path {
+		case "/v1/db/creds/role-1":
+			fmt.Fprint(w, `{"wrap_info":{"token":"wrapping-token","accessor":"accessor-1","ttl":3600,`+
+				`"creation_time":"2023-01-01T00:00:00Z","creation_path":"db/creds/role-1"}}`)
+		case "/v1/sys/control-group/request":
+			var body map[string]string
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body["accessor"] != "accessor-1" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			fmt.Fprintf(w, `{"data":{"approved":%t,"request_path":"db/creds/role-1"}}`, approved)
+		case "/v1/sys/wrapping/unwrap":
+			var body map[string]string
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body["token"] != "wrapping-token" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			fmt.Fprint(w, `{"lease_id":"db/creds/role-1/lease","data":{"username":"user-1"}}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	controlGroupRequests.Purge()
+	t.Cleanup(controlGroupRequests.Purge)
+
+	config := api.DefaultConfig()
+	config.Address = server.URL
+	config.MaxRetries = 0
+	vc, err := api.NewClient(config)
+	require.NoError(t, err)
+	vc.SetToken("client-token")
+
+	c := &defaultClient{
+		client:  vc,
+		connObj: &secretsv1alpha1.VaultConnection{},
+	}
+	ctx := context.Background()
+	uid := types.UID("4b8c5a7e-3f1d-4c59-9d6e-2f0c1b7a8e91")
+
+	// the initial read is held by the control group.
+	_, err = c.Read(ctx, "db/creds/role-1")
+	var cgErr *ControlGroupError
+	require.ErrorAs(t, err, &cgErr)
+	assert.Equal(t, "accessor-1", cgErr.Accessor)
+	assert.Equal(t, "db/creds/role-1", cgErr.Path)
+	assert.Equal(t, time.Hour, cgErr.TTL)
+
+	// the request is unknown until it is tracked for the requesting resource.
+	_, _, err = c.ReadControlGroup(ctx, uid, "accessor-1")
+	assert.ErrorIs(t, err, ErrControlGroupRequestNotFound)
+	TrackControlGroupRequest(uid, cgErr)
+
+	_, _, err = c.ReadControlGroup(ctx, uid, "unknown")
+	assert.ErrorIs(t, err, ErrControlGroupRequestNotFound)
+
+	// another resource can never read the response.
+	_, _, err = c.ReadControlGroup(ctx, "other-uid", "accessor-1")
+	assert.ErrorIs(t, err, ErrControlGroupRequestNotFound)
+
+	// the request has not been authorized yet.
+	_, _, err = c.ReadControlGroup(ctx, uid, "accessor-1")
+	require.ErrorAs(t, err, &cgErr)
+	assert.Equal(t, "accessor-1", cgErr.Accessor)
+
+	mu.Lock()
+	approved = true
+	mu.Unlock()
+
+	got, path, err := c.ReadControlGroup(ctx, uid, "accessor-1")
+	require.NoError(t, err)
+	assert.Equal(t, "db/creds/role-1", path)
+	assert.Equal(t, "db/creds/role-1/lease", got.LeaseID)
+	assert.Equal(t, map[string]any{"username": "user-1"}, got.Data)
+	assert.Equal(t, "client-token", vc.Token())
+
+	// the wrapping token can only be unwrapped once.
+	_, _, err = c.ReadControlGroup(ctx, uid, "accessor-1")
+	assert.ErrorIs(t, err, ErrControlGroupRequestNotFound)
+}
+
+// recordingClient is a Client that records the paths of all requests.
+type recordingClient struct {
+	Client
+	paths []string
+}
+
+func (c *recordingClient) Read(_ context.Context, path string) (*api.Secret, error) {
+	c.paths = append(c.paths, path)
+	return &api.Secret{}, nil
+}
+
+func (c *recordingClient) ReadWithData(_ context.Context, path string, data map[string][]string) (*api.Secret, error) {
+	c.paths = append(c.paths, requestPath(path, data))
+	return &api.Secret{}, nil
+}
+
+func (c *recordingClient) Write(_ context.Context, path string, _ map[string]any) (*api.Secret, error) {
+	c.paths = append(c.paths, path)
+	return &api.Secret{}, nil
+}
+
+func TestWithControlGroupResponse(t *testing.T) {
+	ctx := context.Background()
+	resp := &api.Secret{LeaseID: "lease-1"}
+
+	tests := map[string]struct {
+		path   string
+		do     func(c Client) (*api.Secret, error)
+		replay bool
+	}{
+		"read": {
+			path:   "db/creds/role-1",
+			do:     func(c Client) (*api.Secret, error) { return c.Read(ctx, "db/creds/role-1") },
+			replay: true,
+		},
+		"read-with-data": {
+			path: "kv/data/app?version=2",
+			do: func(c Client) (*api.Secret, error) {
+				return c.ReadWithData(ctx, "kv/data/app", map[string][]string{"version": {"2"}})
+			},
+			replay: true,
+		},
+		"read-with-other-data": {
+			path: "kv/data/app?version=2",
+			do: func(c Client) (*api.Secret, error) {
+				return c.ReadWithData(ctx, "kv/data/app", map[string][]string{"version": {"3"}})
+			},
+		},
+		"write": {
+			path:   "pki/issue/role-1",
+			do:     func(c Client) (*api.Secret, error) { return c.Write(ctx, "pki/issue/role-1", nil) },
+			replay: true,
+		},
+		"other-path": {
+			path: "db/creds/role-1",
+			do:   func(c Client) (*api.Secret, error) { return c.Read(ctx, "db/creds/role-2") },
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			rc := &recordingClient{}
+			c := WithControlGroupResponse(rc, tt.path, resp)
+
+			got, err := tt.do(c)
+			require.NoError(t, err)
+			if tt.replay {
+				assert.Same(t, resp, got)
+				assert.Empty(t, rc.paths)
+			} else {
+				assert.NotSame(t, resp, got)
+				assert.Len(t, rc.paths, 1)
+			}
+
+			// the response is only ever replayed once.
+			got, err = tt.do(c)
+			require.NoError(t, err)
+			assert.NotSame(t, resp, got)
+		})
+	}
+}
@@ -0,0 +1,80 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package credentials
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	secretsv1alpha1 "github.com/hashicorp/vault-secrets-operator/api/v1alpha1"
+)
+
+var _ CredentialProvider = (*AppRoleCredentialProvider)(nil)
+
+type AppRoleCredentialProvider struct {
+	authObj           *secretsv1alpha1.VaultAuth
+	providerNamespace string
+	secretIDSecret    *corev1.Secret
+	uid               types.UID
+}
+
+func (l *AppRoleCredentialProvider) GetNamespace() string {
+	return l.providerNamespace
+}
+
+func (l *AppRoleCredentialProvider) GetUID() types.UID {
+	return l.uid
+}
+
+func (l *AppRoleCredentialProvider) Init(ctx context.Context, client ctrlclient.Client, authObj *secretsv1alpha1.VaultAuth, providerNamespace string) error {
+	l.authObj = authObj
+	l.providerNamespace = providerNamespace
+
+	if l.authObj.Spec.AppRole == nil ||
+		l.authObj.Spec.AppRole.RoleID == "" ||
+		l.authObj.Spec.AppRole.SecretKeyRef.Name == "" ||
+		l.authObj.Spec.AppRole.SecretKeyRef.Key == "" {
+		return fmt.Errorf("roleId and SecretID secret key selector are required to " +
+			"retrieve credentials to authenticate to Vault's AppRole authentication backend")
+	}
+
+	// the SecretID Secret's UID is stable for the lifetime of the
+	// credential source, so it is used as the provider's UID.
+	var err error
+	l.secretIDSecret, err = getSecret(ctx, client, l.providerNamespace, l.authObj.Spec.AppRole.SecretKeyRef.Name)
+	if err != nil {
+		return err
+	}
+	l.uid = l.secretIDSecret.ObjectMeta.UID
+
+	return nil
+}
+
+func (l *AppRoleCredentialProvider) GetCreds(ctx context.Context, client ctrlclient.Client) (map[string]interface{}, error) {
+	logger := log.FromContext(ctx)
+
+	var err error
+	l.secretIDSecret, err = getSecret(ctx, client, l.providerNamespace, l.authObj.Spec.AppRole.SecretKeyRef.Name)
+	if err != nil {
+		logger.Error(err, "Failed to get the AppRole SecretID secret")
+		return nil, err
+	}
+
+	secretID, ok := l.secretIDSecret.Data[l.authObj.Spec.AppRole.SecretKeyRef.Key]
+	if !ok || len(secretID) == 0 {
+		return nil, fmt.Errorf("no data found for key %q in secret %s/%s",
+			l.authObj.Spec.AppRole.SecretKeyRef.Key, l.providerNamespace, l.authObj.Spec.AppRole.SecretKeyRef.Name)
+	}
+
+	// credentials needed for AppRole auth
+	return map[string]interface{}{
+		"role_id":   l.authObj.Spec.AppRole.RoleID,
+		"secret_id": string(secretID),
+	}, nil
+}
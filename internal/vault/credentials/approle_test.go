@@ -0,0 +1,169 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package credentials
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	secretsv1alpha1 "github.com/hashicorp/vault-secrets-operator/api/v1alpha1"
+)
+
+func TestAppRoleCredentialProvider_GetCreds(t *testing.T) {
+	ctx := context.Background()
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "approle",
+			Namespace: "tenant-1",
+			UID:       "a8cfa2bb-5c46-4b4d-b0a1-0d1b5b8d2c61",
+		},
+		Data: map[string][]byte{
+			"id":    []byte("secret-id-1"),
+			"empty": {},
+		},
+	}
+	client := fake.NewClientBuilder().WithObjects(secret).Build()
+
+	tests := []struct {
+		name        string
+		config      *secretsv1alpha1.VaultAuthConfigAppRole
+		want        map[string]interface{}
+		wantInitErr assert.ErrorAssertionFunc
+		wantErr     assert.ErrorAssertionFunc
+	}{
+		{
+			name: "valid",
+			config: &secretsv1alpha1.VaultAuthConfigAppRole{
+				RoleID: "role-id-1",
+				SecretKeyRef: secretsv1alpha1.SecretKeySelector{
+					Name: secret.Name,
+					Key:  "id",
+				},
+			},
+			want: map[string]interface{}{
+				"role_id":   "role-id-1",
+				"secret_id": "secret-id-1",
+			},
+			wantInitErr: assert.NoError,
+			wantErr:     assert.NoError,
+		},
+		{
+			name: "invalid-missing-key",
+			config: &secretsv1alpha1.VaultAuthConfigAppRole{
+				RoleID: "role-id-1",
+				SecretKeyRef: secretsv1alpha1.SecretKeySelector{
+					Name: secret.Name,
+					Key:  "missing",
+				},
+			},
+			wantInitErr: assert.NoError,
+			wantErr:     assert.Error,
+		},
+		{
+			name: "invalid-empty-key",
+			config: &secretsv1alpha1.VaultAuthConfigAppRole{
+				RoleID: "role-id-1",
+				SecretKeyRef: secretsv1alpha1.SecretKeySelector{
+					Name: secret.Name,
+					Key:  "empty",
+				},
+			},
+			wantInitErr: assert.NoError,
+			wantErr:     assert.Error,
+		},
+		{
+			name: "invalid-secret-not-found",
+			config: &secretsv1alpha1.VaultAuthConfigAppRole{
+				RoleID: "role-id-1",
+				SecretKeyRef: secretsv1alpha1.SecretKeySelector{
+					Name: "missing",
+					Key:  "id",
+				},
+			},
+			wantInitErr: assert.Error,
+		},
+		{
+			name: "invalid-no-role-id",
+			config: &secretsv1alpha1.VaultAuthConfigAppRole{
+				SecretKeyRef: secretsv1alpha1.SecretKeySelector{
+					Name: secret.Name,
+					Key:  "id",
+				},
+			},
+			wantInitErr: assert.Error,
+		},
+		{
+			name:        "invalid-no-config",
+			wantInitErr: assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			authObj := &secretsv1alpha1.VaultAuth{
+				Spec: secretsv1alpha1.VaultAuthSpec{
+					Method:  ProviderMethodAppRole,
+					AppRole: tt.config,
+				},
+			}
+			p := &AppRoleCredentialProvider{}
+			err := p.Init(ctx, client, authObj, secret.Namespace)
+			if !tt.wantInitErr(t, err, "Init()") || err != nil {
+				return
+			}
+			assert.Equal(t, secret.Namespace, p.GetNamespace())
+			assert.Equal(t, secret.UID, p.GetUID())
+
+			got, err := p.GetCreds(ctx, client)
+			if !tt.wantErr(t, err, "GetCreds()") || err != nil {
+				return
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestAppRoleCredentialProvider_GetCreds_rotation(t *testing.T) {
+	ctx := context.Background()
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "approle",
+			Namespace: "tenant-1",
+			UID:       "a8cfa2bb-5c46-4b4d-b0a1-0d1b5b8d2c61",
+		},
+		Data: map[string][]byte{
+			"id": []byte("secret-id-1"),
+		},
+	}
+	client := fake.NewClientBuilder().WithObjects(secret).Build()
+
+	p := &AppRoleCredentialProvider{}
+	require.NoError(t, p.Init(ctx, client, &secretsv1alpha1.VaultAuth{
+		Spec: secretsv1alpha1.VaultAuthSpec{
+			Method: ProviderMethodAppRole,
+			AppRole: &secretsv1alpha1.VaultAuthConfigAppRole{
+				RoleID: "role-id-1",
+				SecretKeyRef: secretsv1alpha1.SecretKeySelector{
+					Name: secret.Name,
+					Key:  "id",
+				},
+			},
+		},
+	}, secret.Namespace))
+
+	// the SecretID is read from the Secret on every call.
+	secret.Data["id"] = []byte("secret-id-2")
+	require.NoError(t, client.Update(ctx, secret))
+
+	got, err := p.GetCreds(ctx, client)
+	require.NoError(t, err)
+	assert.Equal(t, "secret-id-2", got["secret_id"])
+}
@@ -0,0 +1,46 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package credentials
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/types"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	secretsv1alpha1 "github.com/hashicorp/vault-secrets-operator/api/v1alpha1"
+)
+
+var _ CredentialProvider = (*AutoAuthCredentialProvider)(nil)
+
+// AutoAuthCredentialProvider is used with a Vault Agent or Vault Proxy that
+// injects its own auto-auth token into every request, so no credentials are ever
+// sent to Vault.
+type AutoAuthCredentialProvider struct {
+	authObj           *secretsv1alpha1.VaultAuth
+	providerNamespace string
+	uid               types.UID
+}
+
+func (l *AutoAuthCredentialProvider) GetNamespace() string {
+	return l.providerNamespace
+}
+
+func (l *AutoAuthCredentialProvider) GetUID() types.UID {
+	return l.uid
+}
+
+func (l *AutoAuthCredentialProvider) Init(_ context.Context, _ ctrlclient.Client, authObj *secretsv1alpha1.VaultAuth, providerNamespace string) error {
+	l.authObj = authObj
+	l.providerNamespace = providerNamespace
+	// the credentials are not backed by a K8s object,
+	// so derive a stable UID from the VaultAuth.
+	l.uid = stableUID(ProviderMethodAutoAuth, string(authObj.UID))
+
+	return nil
+}
+
+func (l *AutoAuthCredentialProvider) GetCreds(_ context.Context, _ ctrlclient.Client) (map[string]interface{}, error) {
+	return nil, nil
+}
@@ -0,0 +1,309 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package credentials
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"k8s.io/apimachinery/pkg/types"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	secretsv1alpha1 "github.com/hashicorp/vault-secrets-operator/api/v1alpha1"
+)
+
+const (
+	AWSDefaultRegion      = "us-east-1"
+	AWSDefaultSessionName = "vault-secrets-operator"
+	// AWSIAMServerIDHeader is the header Vault uses to protect against replay attacks.
+	AWSIAMServerIDHeader = "X-Vault-AWS-IAM-Server-ID"
+
+	awsSTSVersion           = "2011-06-15"
+	awsGetCallerIdentity    = "Action=GetCallerIdentity&Version=" + awsSTSVersion
+	awsFormContentType      = "application/x-www-form-urlencoded; charset=utf-8"
+	awsEnvRoleARN           = "AWS_ROLE_ARN"
+	awsEnvWebIdentityToken  = "AWS_WEB_IDENTITY_TOKEN_FILE"
+	awsSecretAccessKeyIDKey = "access_key_id"
+	awsSecretAccessKeyKey   = "secret_access_key"
+	awsSecretSessionKey     = "session_token"
+)
+
+var _ CredentialProvider = (*AWSCredentialProvider)(nil)
+
+// awsSTSHostRegexp matches the hostnames of the public AWS STS endpoints.
+var awsSTSHostRegexp = regexp.MustCompile(`^sts(-fips)?(\.[a-z0-9-]+)?\.amazonaws\.com(\.cn)?$`)
+
+// awsSTSEndpoints are the additional STS endpoints that a VaultAuth may configure.
+var awsSTSEndpoints []string
+
+// SetAWSSTSEndpoints sets the additional STS endpoints, e.g. VPC endpoints, that a VaultAuth
+// may configure. The public AWS STS endpoints are always allowed.
+func SetAWSSTSEndpoints(endpoints []string) error {
+	var allowed []string
+	for _, e := range endpoints {
+		if e == "" {
+			continue
+		}
+		u, err := url.Parse(e)
+		if err != nil {
+			return err
+		}
+		if u.Scheme == "" || u.Host == "" {
+			return fmt.Errorf("STS endpoint %q must be an absolute URL", e)
+		}
+		allowed = append(allowed, strings.TrimSuffix(e, "/"))
+	}
+	awsSTSEndpoints = allowed
+	return nil
+}
+
+type AWSCredentialProvider struct {
+	authObj           *secretsv1alpha1.VaultAuth
+	providerNamespace string
+	uid               types.UID
+	// now returns the time used for signing requests, it is only ever overridden in tests.
+	now func() time.Time
+	// httpClient is used for exchanging the web identity token with STS.
+	httpClient *http.Client
+}
+
+func (l *AWSCredentialProvider) GetNamespace() string {
+	return l.providerNamespace
+}
+
+func (l *AWSCredentialProvider) GetUID() types.UID {
+	return l.uid
+}
+
+func (l *AWSCredentialProvider) Init(ctx context.Context, client ctrlclient.Client, authObj *secretsv1alpha1.VaultAuth, providerNamespace string) error {
+	l.authObj = authObj
+	l.providerNamespace = providerNamespace
+	if l.now == nil {
+		l.now = time.Now
+	}
+	if l.httpClient == nil {
+		l.httpClient = &http.Client{Timeout: 30 * time.Second}
+	}
+
+	if l.authObj.Spec.AWS == nil || l.authObj.Spec.AWS.Role == "" {
+		return fmt.Errorf("role is required to authenticate to Vault's AWS authentication backend")
+	}
+
+	if err := validateSTSEndpoint(l.stsEndpoint()); err != nil {
+		return err
+	}
+
+	if l.authObj.Spec.AWS.SecretRef != "" {
+		s, err := getSecret(ctx, client, l.providerNamespace, l.authObj.Spec.AWS.SecretRef)
+		if err != nil {
+			return err
+		}
+		l.uid = s.ObjectMeta.UID
+		return nil
+	}
+
+	roleARN, tokenFile := l.irsaConfig()
+	if roleARN == "" || tokenFile == "" {
+		return fmt.Errorf("either an AWS credentials secret or an IRSA role ARN and web identity token file " +
+			"are required to retrieve credentials to authenticate to Vault's AWS authentication backend")
+	}
+
+	// IRSA credentials are not backed by a K8s object,
+	// so derive a stable UID from the role and token file.
+	l.uid = types.UID(uuid.NewSHA1(uuid.NameSpaceURL,
+		[]byte(fmt.Sprintf("%s:%s", roleARN, tokenFile))).String())
+
+	return nil
+}
+
+func (l *AWSCredentialProvider) GetCreds(ctx context.Context, client ctrlclient.Client) (map[string]interface{}, error) {
+	logger := log.FromContext(ctx)
+
+	creds, err := l.getAWSCredentials(ctx, client)
+	if err != nil {
+		logger.Error(err, "Failed to get AWS credentials")
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, l.stsEndpoint(),
+		strings.NewReader(awsGetCallerIdentity))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", awsFormContentType)
+	if l.authObj.Spec.AWS.HeaderValue != "" {
+		req.Header.Set(AWSIAMServerIDHeader, l.authObj.Spec.AWS.HeaderValue)
+	}
+
+	if err := signAWSRequest(req, []byte(awsGetCallerIdentity), creds, l.region(), "sts", l.now()); err != nil {
+		return nil, err
+	}
+
+	headers, err := json.Marshal(req.Header)
+	if err != nil {
+		return nil, err
+	}
+
+	// credentials needed for AWS IAM auth
+	return map[string]interface{}{
+		"role":                    l.authObj.Spec.AWS.Role,
+		"iam_http_request_method": req.Method,
+		"iam_request_url":         base64.StdEncoding.EncodeToString([]byte(req.URL.String())),
+		"iam_request_body":        base64.StdEncoding.EncodeToString([]byte(awsGetCallerIdentity)),
+		"iam_request_headers":     base64.StdEncoding.EncodeToString(headers),
+	}, nil
+}
+
+func (l *AWSCredentialProvider) region() string {
+	if l.authObj.Spec.AWS.Region != "" {
+		return l.authObj.Spec.AWS.Region
+	}
+	return AWSDefaultRegion
+}
+
+func (l *AWSCredentialProvider) stsEndpoint() string {
+	if l.authObj.Spec.AWS.STSEndpoint != "" {
+		return l.authObj.Spec.AWS.STSEndpoint
+	}
+	if r := l.region(); r != AWSDefaultRegion {
+		return fmt.Sprintf("https://sts.%s.amazonaws.com", r)
+	}
+	return "https://sts.amazonaws.com"
+}
+
+// validateSTSEndpoint ensures that endpoint is either a public AWS STS endpoint,
+// or one of the awsSTSEndpoints. The web identity token is sent to it, so it must
+// never be an arbitrary URL.
+func validateSTSEndpoint(endpoint string) error {
+	for _, e := range awsSTSEndpoints {
+		if strings.TrimSuffix(endpoint, "/") == e {
+			return nil
+		}
+	}
+
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return err
+	}
+	if u.Scheme == "https" && u.User == nil && u.Port() == "" &&
+		(u.Path == "" || u.Path == "/") && u.RawQuery == "" &&
+		awsSTSHostRegexp.MatchString(u.Hostname()) {
+		return nil
+	}
+
+	return fmt.Errorf("STS endpoint %q is not an AWS STS endpoint, "+
+		"and is not one of the allowed STS endpoints %v", endpoint, awsSTSEndpoints)
+}
+
+// irsaConfig returns the IAM role and web identity token file that are injected into
+// the Operator's environment by EKS. They are never taken from the VaultAuth, since the
+// token belongs to the Operator.
+func (l *AWSCredentialProvider) irsaConfig() (string, string) {
+	return os.Getenv(awsEnvRoleARN), os.Getenv(awsEnvWebIdentityToken)
+}
+
+func (l *AWSCredentialProvider) getAWSCredentials(ctx context.Context, client ctrlclient.Client) (*awsCredentials, error) {
+	if l.authObj.Spec.AWS.SecretRef != "" {
+		s, err := getSecret(ctx, client, l.providerNamespace, l.authObj.Spec.AWS.SecretRef)
+		if err != nil {
+			return nil, err
+		}
+
+		creds := &awsCredentials{
+			AccessKeyID:     string(s.Data[awsSecretAccessKeyIDKey]),
+			SecretAccessKey: string(s.Data[awsSecretAccessKeyKey]),
+			SessionToken:    string(s.Data[awsSecretSessionKey]),
+		}
+		if creds.AccessKeyID == "" || creds.SecretAccessKey == "" {
+			return nil, fmt.Errorf("secret %s/%s must contain the keys %q and %q",
+				l.providerNamespace, l.authObj.Spec.AWS.SecretRef, awsSecretAccessKeyIDKey, awsSecretAccessKeyKey)
+		}
+		return creds, nil
+	}
+
+	return l.assumeRoleWithWebIdentity(ctx)
+}
+
+type assumeRoleWithWebIdentityResponse struct {
+	Result struct {
+		Credentials struct {
+			AccessKeyID     string `xml:"AccessKeyId"`
+			SecretAccessKey string `xml:"SecretAccessKey"`
+			SessionToken    string `xml:"SessionToken"`
+		} `xml:"Credentials"`
+	} `xml:"AssumeRoleWithWebIdentityResult"`
+}
+
+// assumeRoleWithWebIdentity exchanges the IRSA web identity token for temporary
+// AWS credentials. The token file is read on every call, since it is rotated by the kubelet.
+func (l *AWSCredentialProvider) assumeRoleWithWebIdentity(ctx context.Context) (*awsCredentials, error) {
+	roleARN, tokenFile := l.irsaConfig()
+	token, err := os.ReadFile(tokenFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the web identity token file: %w", err)
+	}
+
+	sessionName := AWSDefaultSessionName
+	if c := l.authObj.Spec.AWS.IRSAConfig; c != nil && c.SessionName != "" {
+		sessionName = c.SessionName
+	}
+
+	form := url.Values{
+		"Action":           {"AssumeRoleWithWebIdentity"},
+		"Version":          {awsSTSVersion},
+		"RoleArn":          {roleARN},
+		"RoleSessionName":  {sessionName},
+		"WebIdentityToken": {strings.TrimSpace(string(token))},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, l.stsEndpoint(),
+		strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", awsFormContentType)
+
+	resp, err := l.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("sts:AssumeRoleWithWebIdentity failed with status %d: %s",
+			resp.StatusCode, strings.TrimSpace(string(b)))
+	}
+
+	var r assumeRoleWithWebIdentityResponse
+	if err := xml.Unmarshal(b, &r); err != nil {
+		return nil, fmt.Errorf("failed to decode the sts:AssumeRoleWithWebIdentity response: %w", err)
+	}
+
+	creds := &awsCredentials{
+		AccessKeyID:     r.Result.Credentials.AccessKeyID,
+		SecretAccessKey: r.Result.Credentials.SecretAccessKey,
+		SessionToken:    r.Result.Credentials.SessionToken,
+	}
+	if creds.AccessKeyID == "" || creds.SecretAccessKey == "" {
+		return nil, fmt.Errorf("sts:AssumeRoleWithWebIdentity response did not contain credentials")
+	}
+
+	return creds, nil
+}
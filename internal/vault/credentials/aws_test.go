@@ -0,0 +1,341 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package credentials
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	secretsv1alpha1 "github.com/hashicorp/vault-secrets-operator/api/v1alpha1"
+)
+
+// test values taken from the AWS SigV4 test suite.
+var (
+	testAWSSigningTime = time.Date(2015, 8, 30, 12, 36, 0, 0, time.UTC)
+	testAWSCredentials = &awsCredentials{
+		AccessKeyID:     "AKIDEXAMPLE",
+		SecretAccessKey: "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY",
+	}
+)
+
+func Test_signAWSRequest(t *testing.T) {
+	tests := []struct {
+		name    string
+		method  string
+		url     string
+		body    string
+		headers map[string]string
+		region  string
+		service string
+		creds   *awsCredentials
+		want    string
+		wantErr assert.ErrorAssertionFunc
+	}{
+		{
+			name:    "get-vanilla",
+			method:  http.MethodGet,
+			url:     "https://example.amazonaws.com/",
+			region:  "us-east-1",
+			service: "service",
+			creds:   testAWSCredentials,
+			want: "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, " +
+				"SignedHeaders=host;x-amz-date, " +
+				"Signature=5fa00fa31553b73ebf1942676e86291e8372ff2a2260956d9b8aae1d763fbf31",
+			wantErr: assert.NoError,
+		},
+		{
+			name:   "get-caller-identity",
+			method: http.MethodPost,
+			url:    "https://sts.amazonaws.com",
+			body:   awsGetCallerIdentity,
+			headers: map[string]string{
+				"Content-Type":       awsFormContentType,
+				AWSIAMServerIDHeader: "vault.example.com",
+			},
+			region:  "us-east-1",
+			service: "sts",
+			creds:   testAWSCredentials,
+			want: "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/sts/aws4_request, " +
+				"SignedHeaders=content-type;host;x-amz-date;x-vault-aws-iam-server-id, " +
+				"Signature=df234c45af42b105eae29dd0035133e7fadb800a3000a94bf0f44b08c7b38cbe",
+			wantErr: assert.NoError,
+		},
+		{
+			name:    "invalid-no-credentials",
+			method:  http.MethodGet,
+			url:     "https://example.amazonaws.com/",
+			region:  "us-east-1",
+			service: "service",
+			creds:   &awsCredentials{},
+			wantErr: assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(tt.method, tt.url, nil)
+			require.NoError(t, err)
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+
+			err = signAWSRequest(req, []byte(tt.body), tt.creds, tt.region, tt.service, testAWSSigningTime)
+			if !tt.wantErr(t, err, fmt.Sprintf("signAWSRequest(%v)", req)) {
+				return
+			}
+			if err == nil {
+				assert.Equal(t, tt.want, req.Header.Get("Authorization"))
+				assert.Equal(t, "20150830T123600Z", req.Header.Get("X-Amz-Date"))
+			}
+		})
+	}
+}
+
+func TestAWSCredentialProvider_GetCreds(t *testing.T) {
+	ctx := context.Background()
+
+	stsServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil || r.Form.Get("WebIdentityToken") != "web-identity-token" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		fmt.Fprint(w, `<AssumeRoleWithWebIdentityResponse>
+  <AssumeRoleWithWebIdentityResult>
+    <Credentials>
+      <AccessKeyId>ASIAEXAMPLE</AccessKeyId>
+      <SecretAccessKey>secret</SecretAccessKey>
+      <SessionToken>session-token</SessionToken>
+    </Credentials>
+  </AssumeRoleWithWebIdentityResult>
+</AssumeRoleWithWebIdentityResponse>`)
+	}))
+	t.Cleanup(stsServer.Close)
+
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(tokenFile, []byte("web-identity-token\n"), 0o600))
+
+	credsSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "aws-creds",
+			Namespace: "tenant-1",
+			UID:       "c4fad6b9-e7bb-4ed8-bc38-67fd6dc85a37",
+		},
+		Data: map[string][]byte{
+			awsSecretAccessKeyIDKey: []byte(testAWSCredentials.AccessKeyID),
+			awsSecretAccessKeyKey:   []byte(testAWSCredentials.SecretAccessKey),
+		},
+	}
+	client := fake.NewClientBuilder().WithObjects(credsSecret).Build()
+	setAWSSTSEndpoints(t, stsServer.URL)
+
+	tests := []struct {
+		name        string
+		config      *secretsv1alpha1.VaultAuthConfigAWS
+		roleARN     string
+		tokenFile   string
+		wantHeaders map[string]string
+		wantInitErr assert.ErrorAssertionFunc
+		wantErr     assert.ErrorAssertionFunc
+	}{
+		{
+			name: "static-credentials",
+			config: &secretsv1alpha1.VaultAuthConfigAWS{
+				Role:        "role-1",
+				HeaderValue: "vault.example.com",
+				SecretRef:   credsSecret.Name,
+			},
+			wantHeaders: map[string]string{
+				"Authorization": "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/sts/aws4_request, " +
+					"SignedHeaders=content-type;host;x-amz-date;x-vault-aws-iam-server-id, " +
+					"Signature=df234c45af42b105eae29dd0035133e7fadb800a3000a94bf0f44b08c7b38cbe",
+				AWSIAMServerIDHeader: "vault.example.com",
+			},
+			wantInitErr: assert.NoError,
+			wantErr:     assert.NoError,
+		},
+		{
+			name: "irsa",
+			config: &secretsv1alpha1.VaultAuthConfigAWS{
+				Role:        "role-1",
+				STSEndpoint: stsServer.URL,
+			},
+			roleARN:   "arn:aws:iam::123456789012:role/vso",
+			tokenFile: tokenFile,
+			wantHeaders: map[string]string{
+				"X-Amz-Security-Token": "session-token",
+			},
+			wantInitErr: assert.NoError,
+			wantErr:     assert.NoError,
+		},
+		{
+			name: "irsa-token-file-missing",
+			config: &secretsv1alpha1.VaultAuthConfigAWS{
+				Role:        "role-1",
+				STSEndpoint: stsServer.URL,
+			},
+			roleARN:     "arn:aws:iam::123456789012:role/vso",
+			tokenFile:   filepath.Join(t.TempDir(), "missing"),
+			wantInitErr: assert.NoError,
+			wantErr:     assert.Error,
+		},
+		{
+			name: "invalid-irsa-not-configured",
+			config: &secretsv1alpha1.VaultAuthConfigAWS{
+				Role:        "role-1",
+				STSEndpoint: stsServer.URL,
+				IRSAConfig: &secretsv1alpha1.AWSIRSAConfig{
+					SessionName: "session-1",
+				},
+			},
+			wantInitErr: assert.Error,
+		},
+		{
+			name: "invalid-sts-endpoint-not-allowed",
+			config: &secretsv1alpha1.VaultAuthConfigAWS{
+				Role:        "role-1",
+				STSEndpoint: "https://sts.example.com",
+			},
+			roleARN:     "arn:aws:iam::123456789012:role/vso",
+			tokenFile:   tokenFile,
+			wantInitErr: assert.Error,
+		},
+		{
+			name: "invalid-no-role",
+			config: &secretsv1alpha1.VaultAuthConfigAWS{
+				SecretRef: credsSecret.Name,
+			},
+			wantInitErr: assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(awsEnvRoleARN, tt.roleARN)
+			t.Setenv(awsEnvWebIdentityToken, tt.tokenFile)
+
+			authObj := &secretsv1alpha1.VaultAuth{
+				Spec: secretsv1alpha1.VaultAuthSpec{
+					Method: ProviderMethodAWS,
+					AWS:    tt.config,
+				},
+			}
+			p := &AWSCredentialProvider{
+				now: func() time.Time {
+					return testAWSSigningTime
+				},
+			}
+			err := p.Init(ctx, client, authObj, credsSecret.Namespace)
+			if !tt.wantInitErr(t, err, "Init()") || err != nil {
+				return
+			}
+			assert.Len(t, p.GetUID(), 36)
+
+			got, err := p.GetCreds(ctx, client)
+			if !tt.wantErr(t, err, "GetCreds()") || err != nil {
+				return
+			}
+
+			assert.Equal(t, tt.config.Role, got["role"])
+			assert.Equal(t, http.MethodPost, got["iam_http_request_method"])
+
+			body, err := base64.StdEncoding.DecodeString(got["iam_request_body"].(string))
+			require.NoError(t, err)
+			assert.Equal(t, awsGetCallerIdentity, string(body))
+
+			b, err := base64.StdEncoding.DecodeString(got["iam_request_headers"].(string))
+			require.NoError(t, err)
+			var headers http.Header
+			require.NoError(t, json.Unmarshal(b, &headers))
+			for k, v := range tt.wantHeaders {
+				assert.Equal(t, v, headers.Get(k), "header %s", k)
+			}
+		})
+	}
+}
+
+func Test_validateSTSEndpoint(t *testing.T) {
+	setAWSSTSEndpoints(t, "https://vpce-1234.sts.us-east-1.vpce.amazonaws.com")
+
+	tests := []struct {
+		name     string
+		endpoint string
+		wantErr  assert.ErrorAssertionFunc
+	}{
+		{
+			name:     "global",
+			endpoint: "https://sts.amazonaws.com",
+			wantErr:  assert.NoError,
+		},
+		{
+			name:     "regional",
+			endpoint: "https://sts.eu-west-1.amazonaws.com/",
+			wantErr:  assert.NoError,
+		},
+		{
+			name:     "fips",
+			endpoint: "https://sts-fips.us-east-1.amazonaws.com",
+			wantErr:  assert.NoError,
+		},
+		{
+			name:     "china",
+			endpoint: "https://sts.cn-north-1.amazonaws.com.cn",
+			wantErr:  assert.NoError,
+		},
+		{
+			name:     "allowed",
+			endpoint: "https://vpce-1234.sts.us-east-1.vpce.amazonaws.com/",
+			wantErr:  assert.NoError,
+		},
+		{
+			name:     "invalid-http",
+			endpoint: "http://sts.amazonaws.com",
+			wantErr:  assert.Error,
+		},
+		{
+			name:     "invalid-port",
+			endpoint: "https://sts.amazonaws.com:8443",
+			wantErr:  assert.Error,
+		},
+		{
+			name:     "invalid-path",
+			endpoint: "https://sts.amazonaws.com/proxy",
+			wantErr:  assert.Error,
+		},
+		{
+			name:     "invalid-host",
+			endpoint: "https://sts.amazonaws.com.example.com",
+			wantErr:  assert.Error,
+		},
+		{
+			name:     "invalid-not-allowed",
+			endpoint: "https://vpce-5678.sts.us-east-1.vpce.amazonaws.com",
+			wantErr:  assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.wantErr(t, validateSTSEndpoint(tt.endpoint), fmt.Sprintf("validateSTSEndpoint(%q)", tt.endpoint))
+		})
+	}
+}
+
+func setAWSSTSEndpoints(t *testing.T, endpoints ...string) {
+	t.Helper()
+	require.NoError(t, SetAWSSTSEndpoints(endpoints))
+	t.Cleanup(func() {
+		awsSTSEndpoints = nil
+	})
+}
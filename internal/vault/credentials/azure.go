@@ -0,0 +1,237 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package credentials
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	secretsv1alpha1 "github.com/hashicorp/vault-secrets-operator/api/v1alpha1"
+)
+
+const (
+	AzureDefaultResource      = "https://management.azure.com/"
+	AzureDefaultIMDSEndpoint  = "http://169.254.169.254/metadata/identity/oauth2/token"
+	AzureDefaultAuthorityHost = "https://login.microsoftonline.com"
+
+	azureIMDSAPIVersion        = "2018-02-01"
+	azureClientAssertionType   = "urn:ietf:params:oauth:client-assertion-type:jwt-bearer"
+	azureEnvClientID           = "AZURE_CLIENT_ID"
+	azureEnvTenantID           = "AZURE_TENANT_ID"
+	azureEnvFederatedTokenFile = "AZURE_FEDERATED_TOKEN_FILE"
+)
+
+var _ CredentialProvider = (*AzureCredentialProvider)(nil)
+
+var (
+	azureIMDSEndpoint  = AzureDefaultIMDSEndpoint
+	azureAuthorityHost = AzureDefaultAuthorityHost
+)
+
+// SetAzureEndpoints sets the IMDS managed identity endpoint, and the Microsoft Entra ID
+// authority host that is used for workload identity. The defaults are used for any empty endpoint.
+func SetAzureEndpoints(imdsEndpoint, authorityHost string) error {
+	if imdsEndpoint == "" {
+		imdsEndpoint = AzureDefaultIMDSEndpoint
+	}
+	if authorityHost == "" {
+		authorityHost = AzureDefaultAuthorityHost
+	}
+	for _, e := range []string{imdsEndpoint, authorityHost} {
+		u, err := url.Parse(e)
+		if err != nil {
+			return err
+		}
+		if u.Scheme == "" || u.Host == "" {
+			return fmt.Errorf("azure endpoint %q must be an absolute URL", e)
+		}
+	}
+
+	azureIMDSEndpoint = imdsEndpoint
+	azureAuthorityHost = strings.TrimSuffix(authorityHost, "/")
+	return nil
+}
+
+type AzureCredentialProvider struct {
+	authObj           *secretsv1alpha1.VaultAuth
+	providerNamespace string
+	uid               types.UID
+	// httpClient is used for requesting access tokens.
+	httpClient *http.Client
+}
+
+func (l *AzureCredentialProvider) GetNamespace() string {
+	return l.providerNamespace
+}
+
+func (l *AzureCredentialProvider) GetUID() types.UID {
+	return l.uid
+}
+
+func (l *AzureCredentialProvider) Init(ctx context.Context, client ctrlclient.Client, authObj *secretsv1alpha1.VaultAuth, providerNamespace string) error {
+	l.authObj = authObj
+	l.providerNamespace = providerNamespace
+	if l.httpClient == nil {
+		l.httpClient = &http.Client{Timeout: 30 * time.Second}
+	}
+
+	if l.authObj.Spec.Azure == nil || l.authObj.Spec.Azure.Role == "" {
+		return fmt.Errorf("role is required to authenticate to Vault's Azure authentication backend")
+	}
+
+	clientID, tenantID, tokenFile := l.workloadIdentityConfig()
+	if tokenFile == "" {
+		clientID = l.authObj.Spec.Azure.ClientID
+	} else if clientID == "" || tenantID == "" {
+		return fmt.Errorf("the %s and %s environment variables are required to "+
+			"retrieve credentials via Azure workload identity", azureEnvClientID, azureEnvTenantID)
+	}
+
+	// managed identity credentials are not backed by a K8s object,
+	// so derive a stable UID from the token source.
+	l.uid = stableUID(ProviderMethodAzure, l.tokenEndpoint(), l.resource(), clientID, tenantID, tokenFile)
+
+	return nil
+}
+
+func (l *AzureCredentialProvider) GetCreds(ctx context.Context, client ctrlclient.Client) (map[string]interface{}, error) {
+	logger := log.FromContext(ctx)
+
+	var token string
+	var err error
+	if _, _, tokenFile := l.workloadIdentityConfig(); tokenFile != "" {
+		token, err = l.getWorkloadIdentityToken(ctx)
+	} else {
+		token, err = l.getManagedIdentityToken(ctx)
+	}
+	if err != nil {
+		logger.Error(err, "Failed to get the Azure access token")
+		return nil, err
+	}
+
+	// credentials needed for Azure auth
+	return map[string]interface{}{
+		"role":                l.authObj.Spec.Azure.Role,
+		"jwt":                 token,
+		"subscription_id":     l.authObj.Spec.Azure.SubscriptionID,
+		"resource_group_name": l.authObj.Spec.Azure.ResourceGroupName,
+		"vm_name":             l.authObj.Spec.Azure.VMName,
+	}, nil
+}
+
+func (l *AzureCredentialProvider) resource() string {
+	if l.authObj.Spec.Azure.Resource != "" {
+		return l.authObj.Spec.Azure.Resource
+	}
+	return AzureDefaultResource
+}
+
+// workloadIdentityConfig returns the workload identity client ID, tenant ID, and federated
+// token file that are injected into the Operator's environment. They are never taken from the
+// VaultAuth, since the federated token belongs to the Operator.
+func (l *AzureCredentialProvider) workloadIdentityConfig() (string, string, string) {
+	tokenFile := os.Getenv(azureEnvFederatedTokenFile)
+	if tokenFile == "" {
+		return "", "", ""
+	}
+	return os.Getenv(azureEnvClientID), os.Getenv(azureEnvTenantID), tokenFile
+}
+
+func (l *AzureCredentialProvider) tokenEndpoint() string {
+	if _, tenantID, tokenFile := l.workloadIdentityConfig(); tokenFile != "" {
+		return fmt.Sprintf("%s/%s/oauth2/v2.0/token", azureAuthorityHost, url.PathEscape(tenantID))
+	}
+	return azureIMDSEndpoint
+}
+
+// getManagedIdentityToken requests an access token from the IMDS managed identity endpoint.
+func (l *AzureCredentialProvider) getManagedIdentityToken(ctx context.Context) (string, error) {
+	u, err := url.Parse(l.tokenEndpoint())
+	if err != nil {
+		return "", err
+	}
+
+	q := u.Query()
+	q.Set("api-version", azureIMDSAPIVersion)
+	q.Set("resource", l.resource())
+	if l.authObj.Spec.Azure.ClientID != "" {
+		q.Set("client_id", l.authObj.Spec.Azure.ClientID)
+	}
+	u.RawQuery = q.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Metadata", "true")
+
+	return l.doTokenRequest(req)
+}
+
+// getWorkloadIdentityToken exchanges the federated token for an access token.
+// The token file is read on every call, since it is rotated by the kubelet.
+func (l *AzureCredentialProvider) getWorkloadIdentityToken(ctx context.Context) (string, error) {
+	clientID, _, tokenFile := l.workloadIdentityConfig()
+	assertion, err := os.ReadFile(tokenFile)
+	if err != nil {
+		return "", fmt.Errorf("failed to read the federated token file: %w", err)
+	}
+
+	form := url.Values{
+		"grant_type":            {"client_credentials"},
+		"client_id":             {clientID},
+		"client_assertion_type": {azureClientAssertionType},
+		"client_assertion":      {strings.TrimSpace(string(assertion))},
+		"scope":                 {strings.TrimSuffix(l.resource(), "/") + "/.default"},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, l.tokenEndpoint(),
+		strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	return l.doTokenRequest(req)
+}
+
+func (l *AzureCredentialProvider) doTokenRequest(req *http.Request) (string, error) {
+	resp, err := l.httpClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("token request to %s failed with status %d: %s",
+			req.URL.Redacted(), resp.StatusCode, strings.TrimSpace(string(b)))
+	}
+
+	var token struct {
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.Unmarshal(b, &token); err != nil {
+		return "", fmt.Errorf("failed to decode the token response: %w", err)
+	}
+	if token.AccessToken == "" {
+		return "", fmt.Errorf("token response did not contain an access token")
+	}
+
+	return token.AccessToken, nil
+}
@@ -0,0 +1,166 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package credentials
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	secretsv1alpha1 "github.com/hashicorp/vault-secrets-operator/api/v1alpha1"
+)
+
+func TestAzureCredentialProvider_GetCreds(t *testing.T) {
+	ctx := context.Background()
+
+	// stands in for both the IMDS endpoint and the Entra ID token endpoint.
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/metadata/identity/oauth2/token":
+			q := r.URL.Query()
+			if r.Header.Get("Metadata") != "true" || q.Get("api-version") != azureIMDSAPIVersion {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			fmt.Fprintf(w, `{"access_token":"msi-%s%s"}`, q.Get("client_id"), q.Get("resource"))
+		case "/tenant-1/oauth2/v2.0/token":
+			if err := r.ParseForm(); err != nil || r.Form.Get("client_assertion") != "federated-token" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			fmt.Fprintf(w, `{"access_token":"wi-%s-%s"}`, r.Form.Get("client_id"), r.Form.Get("scope"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(tokenFile, []byte("federated-token\n"), 0o600))
+
+	require.NoError(t, SetAzureEndpoints(server.URL+"/metadata/identity/oauth2/token", server.URL))
+	t.Cleanup(func() {
+		require.NoError(t, SetAzureEndpoints("", ""))
+	})
+
+	tests := []struct {
+		name        string
+		config      *secretsv1alpha1.VaultAuthConfigAzure
+		env         map[string]string
+		wantJWT     string
+		wantInitErr assert.ErrorAssertionFunc
+		wantErr     assert.ErrorAssertionFunc
+	}{
+		{
+			name: "managed-identity",
+			config: &secretsv1alpha1.VaultAuthConfigAzure{
+				Role:              "role-1",
+				SubscriptionID:    "sub-1",
+				ResourceGroupName: "rg-1",
+				VMName:            "vm-1",
+			},
+			wantJWT:     "msi-" + AzureDefaultResource,
+			wantInitErr: assert.NoError,
+			wantErr:     assert.NoError,
+		},
+		{
+			name: "user-assigned-managed-identity",
+			config: &secretsv1alpha1.VaultAuthConfigAzure{
+				Role:     "role-1",
+				ClientID: "client-1",
+				Resource: "https://vault.example.com/",
+			},
+			wantJWT:     "msi-client-1https://vault.example.com/",
+			wantInitErr: assert.NoError,
+			wantErr:     assert.NoError,
+		},
+		{
+			name: "workload-identity",
+			config: &secretsv1alpha1.VaultAuthConfigAzure{
+				Role: "role-1",
+				// ignored for workload identity
+				ClientID: "client-2",
+			},
+			env: map[string]string{
+				azureEnvClientID:           "client-1",
+				azureEnvTenantID:           "tenant-1",
+				azureEnvFederatedTokenFile: tokenFile,
+			},
+			wantJWT:     "wi-client-1-https://management.azure.com/.default",
+			wantInitErr: assert.NoError,
+			wantErr:     assert.NoError,
+		},
+		{
+			name: "token-endpoint-error",
+			config: &secretsv1alpha1.VaultAuthConfigAzure{
+				Role: "role-1",
+			},
+			env: map[string]string{
+				azureEnvClientID:           "client-1",
+				azureEnvTenantID:           "tenant-2",
+				azureEnvFederatedTokenFile: tokenFile,
+			},
+			wantInitErr: assert.NoError,
+			wantErr:     assert.Error,
+		},
+		{
+			name: "invalid-workload-identity-no-tenant",
+			config: &secretsv1alpha1.VaultAuthConfigAzure{
+				Role: "role-1",
+			},
+			env: map[string]string{
+				azureEnvClientID:           "client-1",
+				azureEnvFederatedTokenFile: tokenFile,
+			},
+			wantInitErr: assert.Error,
+		},
+		{
+			name:        "invalid-no-role",
+			config:      &secretsv1alpha1.VaultAuthConfigAzure{},
+			wantInitErr: assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(azureEnvClientID, tt.env[azureEnvClientID])
+			t.Setenv(azureEnvTenantID, tt.env[azureEnvTenantID])
+			t.Setenv(azureEnvFederatedTokenFile, tt.env[azureEnvFederatedTokenFile])
+
+			authObj := &secretsv1alpha1.VaultAuth{
+				Spec: secretsv1alpha1.VaultAuthSpec{
+					Method: ProviderMethodAzure,
+					Azure:  tt.config,
+				},
+			}
+			client := fake.NewClientBuilder().Build()
+			p := &AzureCredentialProvider{}
+			err := p.Init(ctx, client, authObj, "tenant-1")
+			if !tt.wantInitErr(t, err, "Init()") || err != nil {
+				return
+			}
+			assert.Len(t, p.GetUID(), 36)
+
+			got, err := p.GetCreds(ctx, client)
+			if !tt.wantErr(t, err, "GetCreds()") || err != nil {
+				return
+			}
+
+			assert.Equal(t, map[string]interface{}{
+				"role":                tt.config.Role,
+				"jwt":                 tt.wantJWT,
+				"subscription_id":     tt.config.SubscriptionID,
+				"resource_group_name": tt.config.ResourceGroupName,
+				"vm_name":             tt.config.VMName,
+			}, got)
+		})
+	}
+}
@@ -0,0 +1,139 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package credentials
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	secretsv1alpha1 "github.com/hashicorp/vault-secrets-operator/api/v1alpha1"
+)
+
+var (
+	_ CredentialProvider        = (*CertCredentialProvider)(nil)
+	_ ClientCertificateProvider = (*CertCredentialProvider)(nil)
+)
+
+// ClientCertificateProvider is implemented by any CredentialProvider that
+// authenticates to Vault by presenting a TLS client certificate.
+type ClientCertificateProvider interface {
+	// GetClientCertificate returns the current client certificate,
+	// it is suitable for use as tls.Config.GetClientCertificate.
+	GetClientCertificate(*tls.CertificateRequestInfo) (*tls.Certificate, error)
+}
+
+type CertCredentialProvider struct {
+	authObj           *secretsv1alpha1.VaultAuth
+	providerNamespace string
+	uid               types.UID
+	certPEM           []byte
+	keyPEM            []byte
+	cert              *tls.Certificate
+	mu                sync.RWMutex
+}
+
+func (l *CertCredentialProvider) GetNamespace() string {
+	return l.providerNamespace
+}
+
+func (l *CertCredentialProvider) GetUID() types.UID {
+	return l.uid
+}
+
+func (l *CertCredentialProvider) Init(ctx context.Context, client ctrlclient.Client, authObj *secretsv1alpha1.VaultAuth, providerNamespace string) error {
+	l.authObj = authObj
+	l.providerNamespace = providerNamespace
+
+	if l.authObj.Spec.Cert == nil || l.authObj.Spec.Cert.SecretRef == "" {
+		return fmt.Errorf("a client certificate secret reference is required to " +
+			"retrieve credentials to authenticate to Vault's TLS certificate authentication backend")
+	}
+
+	s, err := l.loadCertificate(ctx, client)
+	if err != nil {
+		return err
+	}
+	l.uid = s.ObjectMeta.UID
+
+	return nil
+}
+
+// GetClientCertificate returns the most recently loaded client certificate.
+func (l *CertCredentialProvider) GetClientCertificate(_ *tls.CertificateRequestInfo) (*tls.Certificate, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	if l.cert == nil {
+		return nil, fmt.Errorf("client certificate not loaded")
+	}
+
+	return l.cert, nil
+}
+
+func (l *CertCredentialProvider) GetCreds(ctx context.Context, client ctrlclient.Client) (map[string]interface{}, error) {
+	logger := log.FromContext(ctx)
+
+	// always reload the certificate, since it may have been rotated since the last login.
+	if _, err := l.loadCertificate(ctx, client); err != nil {
+		logger.Error(err, "Failed to load the client certificate")
+		return nil, err
+	}
+
+	// credentials needed for cert auth, the certificate itself is presented during the TLS handshake.
+	creds := map[string]interface{}{}
+	if l.authObj.Spec.Cert.Role != "" {
+		creds["name"] = l.authObj.Spec.Cert.Role
+	}
+
+	return creds, nil
+}
+
+// loadCertificate from the referenced kubernetes.io/tls Secret. The parsed
+// certificate is only replaced when the Secret's data has changed.
+func (l *CertCredentialProvider) loadCertificate(ctx context.Context, client ctrlclient.Client) (*corev1.Secret, error) {
+	s, err := getSecret(ctx, client, l.providerNamespace, l.authObj.Spec.Cert.SecretRef)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.Type != corev1.SecretTypeTLS {
+		return nil, fmt.Errorf("secret %s/%s must be of type %q, found %q",
+			s.Namespace, s.Name, corev1.SecretTypeTLS, s.Type)
+	}
+
+	certPEM := s.Data[corev1.TLSCertKey]
+	keyPEM := s.Data[corev1.TLSPrivateKeyKey]
+	if len(certPEM) == 0 {
+		return nil, fmt.Errorf("no data found for key %q in secret %s/%s", corev1.TLSCertKey, s.Namespace, s.Name)
+	}
+	if len(keyPEM) == 0 {
+		return nil, fmt.Errorf("no data found for key %q in secret %s/%s", corev1.TLSPrivateKeyKey, s.Namespace, s.Name)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.cert != nil && bytes.Equal(certPEM, l.certPEM) && bytes.Equal(keyPEM, l.keyPEM) {
+		return s, nil
+	}
+
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return nil, fmt.Errorf("invalid client certificate in secret %s/%s: %w", s.Namespace, s.Name, err)
+	}
+
+	l.cert = &cert
+	l.certPEM = certPEM
+	l.keyPEM = keyPEM
+
+	return s, nil
+}
@@ -0,0 +1,278 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package credentials
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	secretsv1alpha1 "github.com/hashicorp/vault-secrets-operator/api/v1alpha1"
+)
+
+// generateTestCert returns a PEM encoded self-signed certificate and private key for cn.
+func generateTestCert(t *testing.T, cn string) ([]byte, []byte) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: cn},
+		NotBefore:    time.Now().Add(-time.Minute),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+// certCommonName returns the subject's common name of cert's leaf certificate.
+func certCommonName(t *testing.T, cert *tls.Certificate) string {
+	t.Helper()
+
+	require.NotEmpty(t, cert.Certificate)
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	require.NoError(t, err)
+	return leaf.Subject.CommonName
+}
+
+func TestCertCredentialProvider_GetCreds(t *testing.T) {
+	ctx := context.Background()
+	certPEM, keyPEM := generateTestCert(t, "client-1")
+
+	newSecret := func(secretType corev1.SecretType, data map[string][]byte) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "client-cert",
+				Namespace: "tenant-1",
+				UID:       "b2f3c1a4-6d0e-4c8a-9f1b-2e7d5a3c9b10",
+			},
+			Type: secretType,
+			Data: data,
+		}
+	}
+
+	tests := []struct {
+		name        string
+		config      *secretsv1alpha1.VaultAuthConfigCert
+		secret      *corev1.Secret
+		want        map[string]interface{}
+		wantInitErr assert.ErrorAssertionFunc
+	}{
+		{
+			name: "valid",
+			config: &secretsv1alpha1.VaultAuthConfigCert{
+				SecretRef: "client-cert",
+				Role:      "app",
+			},
+			secret: newSecret(corev1.SecretTypeTLS, map[string][]byte{
+				corev1.TLSCertKey:       certPEM,
+				corev1.TLSPrivateKeyKey: keyPEM,
+			}),
+			want:        map[string]interface{}{"name": "app"},
+			wantInitErr: assert.NoError,
+		},
+		{
+			name: "valid-no-role",
+			config: &secretsv1alpha1.VaultAuthConfigCert{
+				SecretRef: "client-cert",
+			},
+			secret: newSecret(corev1.SecretTypeTLS, map[string][]byte{
+				corev1.TLSCertKey:       certPEM,
+				corev1.TLSPrivateKeyKey: keyPEM,
+			}),
+			want:        map[string]interface{}{},
+			wantInitErr: assert.NoError,
+		},
+		{
+			name: "invalid-secret-type",
+			config: &secretsv1alpha1.VaultAuthConfigCert{
+				SecretRef: "client-cert",
+			},
+			secret: newSecret(corev1.SecretTypeOpaque, map[string][]byte{
+				corev1.TLSCertKey:       certPEM,
+				corev1.TLSPrivateKeyKey: keyPEM,
+			}),
+			wantInitErr: func(t assert.TestingT, err error, i ...interface{}) bool {
+				return assert.EqualError(t, err,
+					`secret tenant-1/client-cert must be of type "kubernetes.io/tls", found "Opaque"`, i...)
+			},
+		},
+		{
+			name: "invalid-missing-cert",
+			config: &secretsv1alpha1.VaultAuthConfigCert{
+				SecretRef: "client-cert",
+			},
+			secret: newSecret(corev1.SecretTypeTLS, map[string][]byte{
+				corev1.TLSPrivateKeyKey: keyPEM,
+			}),
+			wantInitErr: func(t assert.TestingT, err error, i ...interface{}) bool {
+				return assert.EqualError(t, err,
+					`no data found for key "tls.crt" in secret tenant-1/client-cert`, i...)
+			},
+		},
+		{
+			name: "invalid-missing-key",
+			config: &secretsv1alpha1.VaultAuthConfigCert{
+				SecretRef: "client-cert",
+			},
+			secret: newSecret(corev1.SecretTypeTLS, map[string][]byte{
+				corev1.TLSCertKey: certPEM,
+			}),
+			wantInitErr: func(t assert.TestingT, err error, i ...interface{}) bool {
+				return assert.EqualError(t, err,
+					`no data found for key "tls.key" in secret tenant-1/client-cert`, i...)
+			},
+		},
+		{
+			name: "invalid-key-pair",
+			config: &secretsv1alpha1.VaultAuthConfigCert{
+				SecretRef: "client-cert",
+			},
+			secret: newSecret(corev1.SecretTypeTLS, map[string][]byte{
+				corev1.TLSCertKey:       certPEM,
+				corev1.TLSPrivateKeyKey: []byte("invalid"),
+			}),
+			wantInitErr: assert.Error,
+		},
+		{
+			name: "invalid-missing-secret",
+			config: &secretsv1alpha1.VaultAuthConfigCert{
+				SecretRef: "other",
+			},
+			secret:      newSecret(corev1.SecretTypeTLS, nil),
+			wantInitErr: assert.Error,
+		},
+		{
+			name:        "invalid-no-secret-ref",
+			config:      &secretsv1alpha1.VaultAuthConfigCert{},
+			secret:      newSecret(corev1.SecretTypeTLS, nil),
+			wantInitErr: assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := fake.NewClientBuilder().WithObjects(tt.secret).Build()
+			authObj := &secretsv1alpha1.VaultAuth{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "vault-auth",
+					Namespace: "tenant-1",
+				},
+				Spec: secretsv1alpha1.VaultAuthSpec{
+					Method: ProviderMethodCert,
+					Cert:   tt.config,
+				},
+			}
+
+			p := &CertCredentialProvider{}
+			err := p.Init(ctx, client, authObj, "tenant-1")
+			if !tt.wantInitErr(t, err, "Init()") || err != nil {
+				return
+			}
+			assert.Equal(t, tt.secret.UID, p.GetUID())
+			assert.Equal(t, "tenant-1", p.GetNamespace())
+
+			got, err := p.GetCreds(ctx, client)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+
+			cert, err := p.GetClientCertificate(nil)
+			require.NoError(t, err)
+			assert.Equal(t, "client-1", certCommonName(t, cert))
+		})
+	}
+}
+
+func TestCertCredentialProvider_GetClientCertificate_rotation(t *testing.T) {
+	ctx := context.Background()
+	certPEM, keyPEM := generateTestCert(t, "client-1")
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "client-cert",
+			Namespace: "tenant-1",
+		},
+		Type: corev1.SecretTypeTLS,
+		Data: map[string][]byte{
+			corev1.TLSCertKey:       certPEM,
+			corev1.TLSPrivateKeyKey: keyPEM,
+		},
+	}
+	client := fake.NewClientBuilder().WithObjects(secret).Build()
+
+	p := &CertCredentialProvider{}
+	// the certificate is unavailable until the provider is initialized.
+	_, err := p.GetClientCertificate(nil)
+	assert.Error(t, err)
+
+	require.NoError(t, p.Init(ctx, client, &secretsv1alpha1.VaultAuth{
+		Spec: secretsv1alpha1.VaultAuthSpec{
+			Method: ProviderMethodCert,
+			Cert: &secretsv1alpha1.VaultAuthConfigCert{
+				SecretRef: secret.Name,
+			},
+		},
+	}, "tenant-1"))
+
+	first, err := p.GetClientCertificate(nil)
+	require.NoError(t, err)
+	assert.Equal(t, "client-1", certCommonName(t, first))
+
+	// the same certificate is returned as long as the secret is unchanged.
+	_, err = p.GetCreds(ctx, client)
+	require.NoError(t, err)
+	got, err := p.GetClientCertificate(nil)
+	require.NoError(t, err)
+	assert.Same(t, first, got)
+
+	// the rotated certificate is picked up on the next login.
+	certPEM, keyPEM = generateTestCert(t, "client-2")
+	secret.Data = map[string][]byte{
+		corev1.TLSCertKey:       certPEM,
+		corev1.TLSPrivateKeyKey: keyPEM,
+	}
+	require.NoError(t, client.Update(ctx, secret))
+
+	got, err = p.GetClientCertificate(nil)
+	require.NoError(t, err)
+	assert.Same(t, first, got, "certificate must only be reloaded by GetCreds")
+
+	_, err = p.GetCreds(ctx, client)
+	require.NoError(t, err)
+	got, err = p.GetClientCertificate(nil)
+	require.NoError(t, err)
+	assert.NotSame(t, first, got)
+	assert.Equal(t, "client-2", certCommonName(t, got))
+
+	// an invalid rotation is reported, and the last valid certificate is kept.
+	secret.Data[corev1.TLSPrivateKeyKey] = []byte("invalid")
+	require.NoError(t, client.Update(ctx, secret))
+	_, err = p.GetCreds(ctx, client)
+	assert.Error(t, err)
+	last, err := p.GetClientCertificate(nil)
+	require.NoError(t, err)
+	assert.Same(t, got, last)
+}
@@ -16,9 +16,30 @@ import (
 const (
 	ProviderMethodKubernetes string = "kubernetes"
 	ProviderMethodJWT        string = "jwt"
+	ProviderMethodAppRole    string = "appRole"
+	ProviderMethodCert       string = "cert"
+	ProviderMethodAWS        string = "aws"
+	ProviderMethodGCP        string = "gcp"
+	ProviderMethodAzure      string = "azure"
+	ProviderMethodToken      string = "token"
+	ProviderMethodUserPass   string = "userpass"
+	ProviderMethodLDAP       string = "ldap"
+	ProviderMethodAutoAuth   string = "autoAuth"
 )
 
-var ProviderMethodsSupported = []string{ProviderMethodKubernetes, ProviderMethodJWT}
+var ProviderMethodsSupported = []string{
+	ProviderMethodKubernetes,
+	ProviderMethodJWT,
+	ProviderMethodAppRole,
+	ProviderMethodCert,
+	ProviderMethodAWS,
+	ProviderMethodGCP,
+	ProviderMethodAzure,
+	ProviderMethodToken,
+	ProviderMethodUserPass,
+	ProviderMethodLDAP,
+	ProviderMethodAutoAuth,
+}
 
 type CredentialProvider interface {
 	Init(ctx context.Context, client ctrlclient.Client, object *secretsv1alpha1.VaultAuth, providerNamespace string) error
@@ -45,6 +66,54 @@ func NewCredentialProvider(ctx context.Context, client ctrlclient.Client, authOb
 			return nil, err
 		}
 		return provider, nil
+	case ProviderMethodAppRole:
+		provider := &AppRoleCredentialProvider{}
+		if err := provider.Init(ctx, client, authObj, providerNamespace); err != nil {
+			return nil, err
+		}
+		return provider, nil
+	case ProviderMethodCert:
+		provider := &CertCredentialProvider{}
+		if err := provider.Init(ctx, client, authObj, providerNamespace); err != nil {
+			return nil, err
+		}
+		return provider, nil
+	case ProviderMethodAWS:
+		provider := &AWSCredentialProvider{}
+		if err := provider.Init(ctx, client, authObj, providerNamespace); err != nil {
+			return nil, err
+		}
+		return provider, nil
+	case ProviderMethodGCP:
+		provider := &GCPCredentialProvider{}
+		if err := provider.Init(ctx, client, authObj, providerNamespace); err != nil {
+			return nil, err
+		}
+		return provider, nil
+	case ProviderMethodAzure:
+		provider := &AzureCredentialProvider{}
+		if err := provider.Init(ctx, client, authObj, providerNamespace); err != nil {
+			return nil, err
+		}
+		return provider, nil
+	case ProviderMethodToken:
+		provider := &TokenCredentialProvider{}
+		if err := provider.Init(ctx, client, authObj, providerNamespace); err != nil {
+			return nil, err
+		}
+		return provider, nil
+	case ProviderMethodUserPass, ProviderMethodLDAP:
+		provider := &UserPassCredentialProvider{}
+		if err := provider.Init(ctx, client, authObj, providerNamespace); err != nil {
+			return nil, err
+		}
+		return provider, nil
+	case ProviderMethodAutoAuth:
+		provider := &AutoAuthCredentialProvider{}
+		if err := provider.Init(ctx, client, authObj, providerNamespace); err != nil {
+			return nil, err
+		}
+		return provider, nil
 	default:
 		return nil, fmt.Errorf("unsupported authentication method %s", authObj.Spec.Method)
 	}
@@ -0,0 +1,357 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package credentials
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"k8s.io/apimachinery/pkg/types"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	secretsv1alpha1 "github.com/hashicorp/vault-secrets-operator/api/v1alpha1"
+)
+
+const (
+	GCPDefaultMetadataEndpoint       = "http://metadata.google.internal"
+	GCPDefaultIAMCredentialsEndpoint = "https://iamcredentials.googleapis.com"
+
+	// gcpJWTExpiration must be within the max_jwt_exp configured on the Vault role,
+	// which defaults to 15 minutes.
+	gcpJWTExpiration      = 10 * time.Minute
+	gcpMetadataFlavor     = "Google"
+	gcpDefaultAccountName = "default"
+)
+
+var _ CredentialProvider = (*GCPCredentialProvider)(nil)
+
+var (
+	gcpMetadataEndpoint       = GCPDefaultMetadataEndpoint
+	gcpIAMCredentialsEndpoint = GCPDefaultIAMCredentialsEndpoint
+)
+
+// SetGCPEndpoints sets the GCP metadata server and IAM Credentials API endpoints that are
+// used for Workload Identity. The defaults are used for any empty endpoint.
+func SetGCPEndpoints(metadataEndpoint, iamCredentialsEndpoint string) error {
+	if metadataEndpoint == "" {
+		metadataEndpoint = GCPDefaultMetadataEndpoint
+	}
+	if iamCredentialsEndpoint == "" {
+		iamCredentialsEndpoint = GCPDefaultIAMCredentialsEndpoint
+	}
+	for _, e := range []string{metadataEndpoint, iamCredentialsEndpoint} {
+		u, err := url.Parse(e)
+		if err != nil {
+			return err
+		}
+		if u.Scheme == "" || u.Host == "" {
+			return fmt.Errorf("GCP endpoint %q must be an absolute URL", e)
+		}
+	}
+
+	gcpMetadataEndpoint = strings.TrimSuffix(metadataEndpoint, "/")
+	gcpIAMCredentialsEndpoint = strings.TrimSuffix(iamCredentialsEndpoint, "/")
+	return nil
+}
+
+type GCPCredentialProvider struct {
+	authObj           *secretsv1alpha1.VaultAuth
+	providerNamespace string
+	uid               types.UID
+	// now returns the time used for the JWT claims, it is only ever overridden in tests.
+	now func() time.Time
+	// httpClient is used for calls to the metadata server and the IAM Credentials API.
+	httpClient *http.Client
+}
+
+// gcpServiceAccountKey holds the fields of a GCP service account key JSON
+// that are needed for signing a JWT.
+type gcpServiceAccountKey struct {
+	Type         string `json:"type"`
+	PrivateKeyID string `json:"private_key_id"`
+	PrivateKey   string `json:"private_key"`
+	ClientEmail  string `json:"client_email"`
+}
+
+func (l *GCPCredentialProvider) GetNamespace() string {
+	return l.providerNamespace
+}
+
+func (l *GCPCredentialProvider) GetUID() types.UID {
+	return l.uid
+}
+
+func (l *GCPCredentialProvider) Init(ctx context.Context, client ctrlclient.Client, authObj *secretsv1alpha1.VaultAuth, providerNamespace string) error {
+	l.authObj = authObj
+	l.providerNamespace = providerNamespace
+	if l.now == nil {
+		l.now = time.Now
+	}
+	if l.httpClient == nil {
+		l.httpClient = &http.Client{Timeout: 30 * time.Second}
+	}
+
+	if l.authObj.Spec.GCP == nil || l.authObj.Spec.GCP.Role == "" {
+		return fmt.Errorf("role is required to authenticate to Vault's GCP authentication backend")
+	}
+
+	if ref := l.authObj.Spec.GCP.SecretKeyRef; ref != nil {
+		if ref.Name == "" || ref.Key == "" {
+			return fmt.Errorf("service account key secret key selector requires both a name and a key")
+		}
+		s, err := getSecret(ctx, client, l.providerNamespace, ref.Name)
+		if err != nil {
+			return err
+		}
+		l.uid = s.ObjectMeta.UID
+		return nil
+	}
+
+	// Workload Identity credentials are not backed by a K8s object,
+	// so derive a stable UID from the metadata endpoint and service account.
+	l.uid = types.UID(uuid.NewSHA1(uuid.NameSpaceURL,
+		[]byte(fmt.Sprintf("%s:%s", l.metadataEndpoint(), l.workloadIdentityAccount()))).String())
+
+	return nil
+}
+
+func (l *GCPCredentialProvider) GetCreds(ctx context.Context, client ctrlclient.Client) (map[string]interface{}, error) {
+	logger := log.FromContext(ctx)
+
+	var jwt string
+	var err error
+	if l.authObj.Spec.GCP.SecretKeyRef != nil {
+		jwt, err = l.signJWTWithKey(ctx, client)
+	} else {
+		jwt, err = l.signJWTWithWorkloadIdentity(ctx)
+	}
+	if err != nil {
+		logger.Error(err, "Failed to sign the GCP JWT")
+		return nil, err
+	}
+
+	// credentials needed for GCP IAM auth
+	return map[string]interface{}{
+		"role": l.authObj.Spec.GCP.Role,
+		"jwt":  jwt,
+	}, nil
+}
+
+func (l *GCPCredentialProvider) metadataEndpoint() string {
+	return gcpMetadataEndpoint
+}
+
+func (l *GCPCredentialProvider) iamCredentialsEndpoint() string {
+	return gcpIAMCredentialsEndpoint
+}
+
+func (l *GCPCredentialProvider) workloadIdentityAccount() string {
+	if l.authObj.Spec.GCP.WorkloadIdentityServiceAccount != "" {
+		return l.authObj.Spec.GCP.WorkloadIdentityServiceAccount
+	}
+	return gcpDefaultAccountName
+}
+
+// jwtClaims returns the claims that Vault's GCP IAM auth expects for the
+// provided service account email.
+func (l *GCPCredentialProvider) jwtClaims(email string) map[string]interface{} {
+	return map[string]interface{}{
+		"sub": email,
+		"aud": fmt.Sprintf("vault/%s", l.authObj.Spec.GCP.Role),
+		"exp": l.now().Add(gcpJWTExpiration).Unix(),
+	}
+}
+
+// signJWTWithKey signs the JWT locally with the service account key
+// referenced by the VaultAuth's SecretKeyRef.
+func (l *GCPCredentialProvider) signJWTWithKey(ctx context.Context, client ctrlclient.Client) (string, error) {
+	ref := l.authObj.Spec.GCP.SecretKeyRef
+	s, err := getSecret(ctx, client, l.providerNamespace, ref.Name)
+	if err != nil {
+		return "", err
+	}
+
+	b, ok := s.Data[ref.Key]
+	if !ok || len(b) == 0 {
+		return "", fmt.Errorf("no data found for key %q in secret %s/%s", ref.Key, l.providerNamespace, ref.Name)
+	}
+
+	var key gcpServiceAccountKey
+	if err := json.Unmarshal(b, &key); err != nil {
+		return "", fmt.Errorf("invalid service account key in secret %s/%s: %w", l.providerNamespace, ref.Name, err)
+	}
+	if key.ClientEmail == "" || key.PrivateKey == "" {
+		return "", fmt.Errorf("service account key in secret %s/%s requires client_email and private_key",
+			l.providerNamespace, ref.Name)
+	}
+
+	signer, err := parseRSAPrivateKey([]byte(key.PrivateKey))
+	if err != nil {
+		return "", err
+	}
+
+	header := map[string]interface{}{
+		"alg": "RS256",
+		"typ": "JWT",
+	}
+	if key.PrivateKeyID != "" {
+		header["kid"] = key.PrivateKeyID
+	}
+
+	return signRS256JWT(header, l.jwtClaims(key.ClientEmail), signer)
+}
+
+// signJWTWithWorkloadIdentity has the IAM Credentials API sign the JWT, authenticating with
+// the Workload Identity access token obtained from the metadata server.
+func (l *GCPCredentialProvider) signJWTWithWorkloadIdentity(ctx context.Context) (string, error) {
+	account := url.PathEscape(l.workloadIdentityAccount())
+	basePath := fmt.Sprintf("%s/computeMetadata/v1/instance/service-accounts/%s", l.metadataEndpoint(), account)
+
+	email, err := l.getMetadata(ctx, basePath+"/email")
+	if err != nil {
+		return "", err
+	}
+
+	b, err := l.getMetadata(ctx, basePath+"/token")
+	if err != nil {
+		return "", err
+	}
+	var token struct {
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.Unmarshal([]byte(b), &token); err != nil {
+		return "", fmt.Errorf("failed to decode the metadata server token response: %w", err)
+	}
+
+	claims, err := json.Marshal(l.jwtClaims(email))
+	if err != nil {
+		return "", err
+	}
+	payload, err := json.Marshal(map[string]string{
+		"payload": string(claims),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost,
+		fmt.Sprintf("%s/v1/projects/-/serviceAccounts/%s:signJwt", l.iamCredentialsEndpoint(), url.PathEscape(email)),
+		bytes.NewReader(payload))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token.AccessToken)
+
+	body, err := l.do(req)
+	if err != nil {
+		return "", err
+	}
+
+	var resp struct {
+		SignedJWT string `json:"signedJwt"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return "", fmt.Errorf("failed to decode the signJwt response: %w", err)
+	}
+	if resp.SignedJWT == "" {
+		return "", fmt.Errorf("signJwt response did not contain a signed JWT")
+	}
+
+	return resp.SignedJWT, nil
+}
+
+func (l *GCPCredentialProvider) getMetadata(ctx context.Context, u string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Metadata-Flavor", gcpMetadataFlavor)
+
+	b, err := l.do(req)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(string(b)), nil
+}
+
+func (l *GCPCredentialProvider) do(req *http.Request) ([]byte, error) {
+	resp, err := l.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("request to %s failed with status %d: %s",
+			req.URL.Redacted(), resp.StatusCode, strings.TrimSpace(string(b)))
+	}
+
+	return b, nil
+}
+
+func parseRSAPrivateKey(b []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(b)
+	if block == nil {
+		return nil, fmt.Errorf("failed to decode the PEM encoded private key")
+	}
+
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the private key: %w", err)
+	}
+
+	rsaKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("unsupported private key type %T, an RSA key is required", key)
+	}
+
+	return rsaKey, nil
+}
+
+func signRS256JWT(header, claims map[string]interface{}, key *rsa.PrivateKey) (string, error) {
+	h, err := json.Marshal(header)
+	if err != nil {
+		return "", err
+	}
+	c, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+
+	signingInput := base64.RawURLEncoding.EncodeToString(h) + "." + base64.RawURLEncoding.EncodeToString(c)
+	digest := sha256.Sum256([]byte(signingInput))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", err
+	}
+
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(sig), nil
+}
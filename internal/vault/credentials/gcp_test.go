@@ -0,0 +1,215 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package credentials
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	secretsv1alpha1 "github.com/hashicorp/vault-secrets-operator/api/v1alpha1"
+)
+
+func TestGCPCredentialProvider_GetCreds(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2023, 5, 1, 0, 0, 0, 0, time.UTC)
+	email := "vso@project.iam.gserviceaccount.com"
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+	saKey, err := json.Marshal(gcpServiceAccountKey{
+		Type:         "service_account",
+		PrivateKeyID: "key-id",
+		PrivateKey:   string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER})),
+		ClientEmail:  email,
+	})
+	require.NoError(t, err)
+
+	// stands in for both the metadata server and the IAM Credentials API.
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/computeMetadata/v1/instance/service-accounts/default/email":
+			if r.Header.Get("Metadata-Flavor") != gcpMetadataFlavor {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			fmt.Fprint(w, email)
+		case "/computeMetadata/v1/instance/service-accounts/default/token":
+			fmt.Fprint(w, `{"access_token":"access-token","expires_in":3599,"token_type":"Bearer"}`)
+		case fmt.Sprintf("/v1/projects/-/serviceAccounts/%s:signJwt", email):
+			if r.Header.Get("Authorization") != "Bearer access-token" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			var req map[string]string
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			fmt.Fprintf(w, `{"keyId":"key-id","signedJwt":%q}`, "signed."+req["payload"])
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+	require.NoError(t, SetGCPEndpoints(server.URL, server.URL))
+	t.Cleanup(func() {
+		require.NoError(t, SetGCPEndpoints("", ""))
+	})
+
+	keySecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "gcp-sa-key",
+			Namespace: "tenant-1",
+			UID:       "c4fad6b9-e7bb-4ed8-bc38-67fd6dc85a37",
+		},
+		Data: map[string][]byte{
+			"key.json": saKey,
+		},
+	}
+	client := fake.NewClientBuilder().WithObjects(keySecret).Build()
+
+	wantClaims := map[string]interface{}{
+		"sub": email,
+		"aud": "vault/role-1",
+		"exp": float64(now.Add(gcpJWTExpiration).Unix()),
+	}
+
+	tests := []struct {
+		name        string
+		config      *secretsv1alpha1.VaultAuthConfigGCP
+		wantUID     string
+		assertJWT   func(t *testing.T, jwt string)
+		wantInitErr assert.ErrorAssertionFunc
+		wantErr     assert.ErrorAssertionFunc
+	}{
+		{
+			name: "service-account-key",
+			config: &secretsv1alpha1.VaultAuthConfigGCP{
+				Role: "role-1",
+				SecretKeyRef: &secretsv1alpha1.SecretKeySelector{
+					Name: keySecret.Name,
+					Key:  "key.json",
+				},
+			},
+			wantUID: string(keySecret.UID),
+			assertJWT: func(t *testing.T, jwt string) {
+				parts := strings.Split(jwt, ".")
+				require.Len(t, parts, 3)
+
+				var header map[string]interface{}
+				b, err := base64.RawURLEncoding.DecodeString(parts[0])
+				require.NoError(t, err)
+				require.NoError(t, json.Unmarshal(b, &header))
+				assert.Equal(t, map[string]interface{}{"alg": "RS256", "typ": "JWT", "kid": "key-id"}, header)
+
+				var claims map[string]interface{}
+				b, err = base64.RawURLEncoding.DecodeString(parts[1])
+				require.NoError(t, err)
+				require.NoError(t, json.Unmarshal(b, &claims))
+				assert.Equal(t, wantClaims, claims)
+
+				sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+				require.NoError(t, err)
+				digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+				assert.NoError(t, rsa.VerifyPKCS1v15(&key.PublicKey, crypto.SHA256, digest[:], sig))
+			},
+			wantInitErr: assert.NoError,
+			wantErr:     assert.NoError,
+		},
+		{
+			name: "workload-identity",
+			config: &secretsv1alpha1.VaultAuthConfigGCP{
+				Role: "role-1",
+			},
+			assertJWT: func(t *testing.T, jwt string) {
+				require.True(t, strings.HasPrefix(jwt, "signed."))
+				var claims map[string]interface{}
+				require.NoError(t, json.Unmarshal([]byte(strings.TrimPrefix(jwt, "signed.")), &claims))
+				assert.Equal(t, wantClaims, claims)
+			},
+			wantInitErr: assert.NoError,
+			wantErr:     assert.NoError,
+		},
+		{
+			name: "workload-identity-unknown-account",
+			config: &secretsv1alpha1.VaultAuthConfigGCP{
+				Role:                           "role-1",
+				WorkloadIdentityServiceAccount: "other@project.iam.gserviceaccount.com",
+			},
+			wantInitErr: assert.NoError,
+			wantErr:     assert.Error,
+		},
+		{
+			name: "invalid-secret-key-missing",
+			config: &secretsv1alpha1.VaultAuthConfigGCP{
+				Role: "role-1",
+				SecretKeyRef: &secretsv1alpha1.SecretKeySelector{
+					Name: keySecret.Name,
+					Key:  "missing",
+				},
+			},
+			wantUID:     string(keySecret.UID),
+			wantInitErr: assert.NoError,
+			wantErr:     assert.Error,
+		},
+		{
+			name:        "invalid-no-role",
+			config:      &secretsv1alpha1.VaultAuthConfigGCP{},
+			wantInitErr: assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			authObj := &secretsv1alpha1.VaultAuth{
+				Spec: secretsv1alpha1.VaultAuthSpec{
+					Method: ProviderMethodGCP,
+					GCP:    tt.config,
+				},
+			}
+			p := &GCPCredentialProvider{
+				now: func() time.Time {
+					return now
+				},
+			}
+			err := p.Init(ctx, client, authObj, keySecret.Namespace)
+			if !tt.wantInitErr(t, err, "Init()") || err != nil {
+				return
+			}
+			if tt.wantUID != "" {
+				assert.Equal(t, tt.wantUID, string(p.GetUID()))
+			} else {
+				assert.Len(t, p.GetUID(), 36)
+			}
+
+			got, err := p.GetCreds(ctx, client)
+			if !tt.wantErr(t, err, "GetCreds()") || err != nil {
+				return
+			}
+
+			assert.Equal(t, tt.config.Role, got["role"])
+			tt.assertJWT(t, got["jwt"].(string))
+		})
+	}
+}
@@ -5,10 +5,18 @@ package credentials
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
 
+	"github.com/google/uuid"
 	authv1 "k8s.io/api/authentication/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/utils/pointer"
 	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -46,3 +54,89 @@ func getSecret(ctx context.Context, client ctrlclient.Client, namespace, name st
 
 	return secret, nil
 }
+
+// stableUID returns a UID that is derived from parts. It should be used by
+// providers whose credentials are not backed by a K8s object.
+func stableUID(parts ...string) types.UID {
+	return types.UID(uuid.NewSHA1(uuid.NameSpaceURL, []byte(strings.Join(parts, ":"))).String())
+}
+
+// tokenFileDirs are the directories that a VaultAuth's token file must be located in.
+// All token files are rejected if it is empty.
+var tokenFileDirs []string
+
+// SetTokenFileDirs sets the directories that a VaultAuth's token file must be located in.
+// Any token in these directories can be used by all VaultAuths, so they should only contain
+// tokens that are intended to be shared, never the operator's own ServiceAccount token.
+func SetTokenFileDirs(dirs []string) error {
+	var cleaned []string
+	for _, dir := range dirs {
+		if dir == "" {
+			continue
+		}
+		if !filepath.IsAbs(dir) {
+			return fmt.Errorf("token file directory %q must be an absolute path", dir)
+		}
+		cleaned = append(cleaned, filepath.Clean(dir))
+	}
+	tokenFileDirs = cleaned
+	return nil
+}
+
+// validateTokenFile ensures that path is suitable for use with readTokenFile,
+// it must be located in one of the tokenFileDirs. It should be called before
+// every read, since the file's symlinks could have changed.
+func validateTokenFile(path string) error {
+	if !filepath.IsAbs(path) {
+		return fmt.Errorf("token file %q must be an absolute path", path)
+	}
+	if len(tokenFileDirs) == 0 {
+		return fmt.Errorf("token file %q is not allowed, no token file directories are configured", path)
+	}
+
+	// projected volume files are symlinks, so the resolved path must also be checked,
+	// it is only known once the file exists.
+	resolved, err := filepath.EvalSymlinks(path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	for _, dir := range tokenFileDirs {
+		if !isWithinDir(dir, path) {
+			continue
+		}
+		if resolved == "" {
+			return nil
+		}
+		if resolvedDir, err := filepath.EvalSymlinks(dir); err == nil && isWithinDir(resolvedDir, resolved) {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("token file %q is not located in any of the allowed directories %v", path, tokenFileDirs)
+}
+
+// isWithinDir returns true if path is located below dir.
+func isWithinDir(dir, path string) bool {
+	rel, err := filepath.Rel(dir, filepath.Clean(path))
+	if err != nil {
+		return false
+	}
+	return rel != "." && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// readTokenFile returns the token stored in the file at path. Projected
+// ServiceAccount tokens are rotated by the kubelet, so the file should be read
+// each time the token is needed.
+func readTokenFile(path string) (string, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read the token file: %w", err)
+	}
+
+	token := strings.TrimSpace(string(b))
+	if token == "" {
+		return "", fmt.Errorf("token file %q is empty", path)
+	}
+
+	return token, nil
+}
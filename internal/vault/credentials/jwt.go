@@ -36,7 +36,14 @@ func (l *JWTCredentialProvider) Init(ctx context.Context, client ctrlclient.Clie
 	l.authObj = authObj
 	l.providerNamespace = providerNamespace
 
-	if l.authObj.Spec.JWT.ServiceAccount != "" {
+	if l.authObj.Spec.JWT.TokenFile != "" {
+		if err := validateTokenFile(l.authObj.Spec.JWT.TokenFile); err != nil {
+			return err
+		}
+		// the token file is not backed by a K8s object,
+		// so derive a stable UID from its path.
+		l.uid = stableUID(ProviderMethodJWT, l.authObj.Spec.JWT.TokenFile)
+	} else if l.authObj.Spec.JWT.ServiceAccount != "" {
 		sa, err := l.getServiceAccount(ctx, client)
 		if err != nil {
 			return err
@@ -52,7 +59,7 @@ func (l *JWTCredentialProvider) Init(ctx context.Context, client ctrlclient.Clie
 		}
 		l.uid = l.tokenSecret.ObjectMeta.UID
 	} else {
-		return fmt.Errorf("either serviceAccount or JWT token secret key selector is required to " +
+		return fmt.Errorf("either tokenFile, serviceAccount or JWT token secret key selector is required to " +
 			"retrieve credentials to authenticate to Vault's JWT authentication backend")
 	}
 
@@ -74,6 +81,23 @@ func (l *JWTCredentialProvider) getServiceAccount(ctx context.Context, client ct
 func (l *JWTCredentialProvider) GetCreds(ctx context.Context, client ctrlclient.Client) (map[string]interface{}, error) {
 	logger := log.FromContext(ctx)
 
+	if l.authObj.Spec.JWT.TokenFile != "" {
+		if err := validateTokenFile(l.authObj.Spec.JWT.TokenFile); err != nil {
+			logger.Error(err, "Invalid JWT token file")
+			return nil, err
+		}
+		token, err := readTokenFile(l.authObj.Spec.JWT.TokenFile)
+		if err != nil {
+			logger.Error(err, "Failed to read JWT token file")
+			return nil, err
+		}
+
+		return map[string]interface{}{
+			"role": l.authObj.Spec.JWT.Role,
+			"jwt":  token,
+		}, nil
+	}
+
 	if l.authObj.Spec.JWT.ServiceAccount != "" {
 		sa, err := l.getServiceAccount(ctx, client)
 		if err != nil {
@@ -81,7 +105,7 @@ func (l *JWTCredentialProvider) GetCreds(ctx context.Context, client ctrlclient.
 			return nil, err
 		}
 
-		tr, err := requestSAToken(ctx, client, sa, l.authObj.Spec.JWT.TokenExpirationSeconds, l.authObj.Spec.JWT.TokenAudiences)
+		token, err := tokenRequestCaches[ProviderMethodJWT].requestSAToken(ctx, client, sa, l.authObj.Spec.JWT.TokenExpirationSeconds, l.authObj.Spec.JWT.TokenAudiences)
 		if err != nil {
 			logger.Error(err, "Failed to get service account token")
 			return nil, err
@@ -90,7 +114,7 @@ func (l *JWTCredentialProvider) GetCreds(ctx context.Context, client ctrlclient.
 		// credentials needed for JWT auth
 		return map[string]interface{}{
 			"role": l.authObj.Spec.JWT.Role,
-			"jwt":  tr.Status.Token,
+			"jwt":  token,
 		}, nil
 	}
 
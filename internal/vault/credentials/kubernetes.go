@@ -5,6 +5,7 @@ package credentials
 
 import (
 	"context"
+	"fmt"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
@@ -44,6 +45,21 @@ func (l *KubernetesCredentialProvider) Init(ctx context.Context, client ctrlclie
 	l.authObj = authObj
 	l.providerNamespace = providerNamespace
 
+	if tokenFile := l.authObj.Spec.Kubernetes.TokenFile; tokenFile != "" {
+		if err := validateTokenFile(tokenFile); err != nil {
+			return err
+		}
+		// the token file is not backed by a K8s object,
+		// so derive a stable UID from its path.
+		l.uid = stableUID(ProviderMethodKubernetes, tokenFile)
+		return nil
+	}
+
+	if l.authObj.Spec.Kubernetes.ServiceAccount == "" {
+		return fmt.Errorf("either serviceAccount or tokenFile is required to " +
+			"retrieve credentials to authenticate to Vault's kubernetes authentication backend")
+	}
+
 	sa, err := l.getServiceAccount(ctx, client)
 	if err != nil {
 		return err
@@ -69,13 +85,30 @@ func (l *KubernetesCredentialProvider) getServiceAccount(ctx context.Context, cl
 func (l *KubernetesCredentialProvider) GetCreds(ctx context.Context, client ctrlclient.Client) (map[string]interface{}, error) {
 	logger := log.FromContext(ctx)
 
+	if tokenFile := l.authObj.Spec.Kubernetes.TokenFile; tokenFile != "" {
+		if err := validateTokenFile(tokenFile); err != nil {
+			logger.Error(err, "Invalid service account token file")
+			return nil, err
+		}
+		token, err := readTokenFile(tokenFile)
+		if err != nil {
+			logger.Error(err, "Failed to read service account token file")
+			return nil, err
+		}
+
+		return map[string]interface{}{
+			"role": l.authObj.Spec.Kubernetes.Role,
+			"jwt":  token,
+		}, nil
+	}
+
 	sa, err := l.getServiceAccount(ctx, client)
 	if err != nil {
 		logger.Error(err, "Failed to get service account")
 		return nil, err
 	}
 
-	tr, err := requestSAToken(ctx, client, sa, l.authObj.Spec.Kubernetes.TokenExpirationSeconds, l.authObj.Spec.Kubernetes.TokenAudiences)
+	token, err := tokenRequestCaches[ProviderMethodKubernetes].requestSAToken(ctx, client, sa, l.authObj.Spec.Kubernetes.TokenExpirationSeconds, l.authObj.Spec.Kubernetes.TokenAudiences)
 	if err != nil {
 		logger.Error(err, "Failed to get service account token")
 		return nil, err
@@ -84,6 +117,6 @@ func (l *KubernetesCredentialProvider) GetCreds(ctx context.Context, client ctrl
 	// credentials needed for Kubernetes auth
 	return map[string]interface{}{
 		"role": l.authObj.Spec.Kubernetes.Role,
-		"jwt":  tr.Status.Token,
+		"jwt":  token,
 	}, nil
 }
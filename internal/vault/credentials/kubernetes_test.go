@@ -0,0 +1,195 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package credentials
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	secretsv1alpha1 "github.com/hashicorp/vault-secrets-operator/api/v1alpha1"
+)
+
+func TestTokenFileCredentialProviders(t *testing.T) {
+	ctx := context.Background()
+	client := fake.NewClientBuilder().Build()
+
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	setTokenFileDirs(t, filepath.Dir(tokenFile))
+
+	tests := []struct {
+		name        string
+		spec        secretsv1alpha1.VaultAuthSpec
+		provider    CredentialProvider
+		wantInitErr assert.ErrorAssertionFunc
+	}{
+		{
+			name: "kubernetes",
+			spec: secretsv1alpha1.VaultAuthSpec{
+				Method: ProviderMethodKubernetes,
+				Kubernetes: &secretsv1alpha1.VaultAuthConfigKubernetes{
+					Role:      "role-1",
+					TokenFile: tokenFile,
+				},
+			},
+			provider:    &KubernetesCredentialProvider{},
+			wantInitErr: assert.NoError,
+		},
+		{
+			name: "jwt",
+			spec: secretsv1alpha1.VaultAuthSpec{
+				Method: ProviderMethodJWT,
+				JWT: &secretsv1alpha1.VaultAuthConfigJWT{
+					Role:      "role-1",
+					TokenFile: tokenFile,
+				},
+			},
+			provider:    &JWTCredentialProvider{},
+			wantInitErr: assert.NoError,
+		},
+		{
+			name: "kubernetes-relative-path",
+			spec: secretsv1alpha1.VaultAuthSpec{
+				Method: ProviderMethodKubernetes,
+				Kubernetes: &secretsv1alpha1.VaultAuthConfigKubernetes{
+					Role:      "role-1",
+					TokenFile: "token",
+				},
+			},
+			provider:    &KubernetesCredentialProvider{},
+			wantInitErr: assert.Error,
+		},
+		{
+			name: "kubernetes-no-source",
+			spec: secretsv1alpha1.VaultAuthSpec{
+				Method: ProviderMethodKubernetes,
+				Kubernetes: &secretsv1alpha1.VaultAuthConfigKubernetes{
+					Role: "role-1",
+				},
+			},
+			provider:    &KubernetesCredentialProvider{},
+			wantInitErr: assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			authObj := &secretsv1alpha1.VaultAuth{Spec: tt.spec}
+			err := tt.provider.Init(ctx, client, authObj, "tenant-1")
+			if !tt.wantInitErr(t, err) || err != nil {
+				return
+			}
+
+			assert.Equal(t, stableUID(tt.spec.Method, tokenFile), tt.provider.GetUID())
+
+			require.NoError(t, os.RemoveAll(tokenFile))
+			_, err = tt.provider.GetCreds(ctx, client)
+			assert.Error(t, err)
+
+			// the file must be re-read on every call to pick up token rotations.
+			for _, token := range []string{"token-1", "token-2"} {
+				require.NoError(t, os.WriteFile(tokenFile, []byte(token+"\n"), 0o600))
+				got, err := tt.provider.GetCreds(ctx, client)
+				require.NoError(t, err)
+				assert.Equal(t, map[string]interface{}{
+					"role": "role-1",
+					"jwt":  token,
+				}, got)
+			}
+		})
+	}
+}
+
+func TestValidateTokenFile(t *testing.T) {
+	allowedDir := t.TempDir()
+	otherDir := t.TempDir()
+	for _, dir := range []string{allowedDir, otherDir} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "token"), []byte("token"), 0o600))
+	}
+	require.NoError(t, os.Symlink(filepath.Join(otherDir, "token"), filepath.Join(allowedDir, "escape")))
+	require.NoError(t, os.Symlink("token", filepath.Join(allowedDir, "projected")))
+
+	tests := []struct {
+		name    string
+		dirs    []string
+		path    string
+		wantErr assert.ErrorAssertionFunc
+	}{
+		{
+			name:    "allowed",
+			dirs:    []string{allowedDir},
+			path:    filepath.Join(allowedDir, "token"),
+			wantErr: assert.NoError,
+		},
+		{
+			name:    "allowed-not-exist",
+			dirs:    []string{allowedDir},
+			path:    filepath.Join(allowedDir, "missing"),
+			wantErr: assert.NoError,
+		},
+		{
+			name:    "allowed-symlink-within-dir",
+			dirs:    []string{allowedDir},
+			path:    filepath.Join(allowedDir, "projected"),
+			wantErr: assert.NoError,
+		},
+		{
+			name:    "no-allowed-dirs",
+			path:    filepath.Join(allowedDir, "token"),
+			wantErr: assert.Error,
+		},
+		{
+			name:    "operator-service-account-token",
+			dirs:    []string{allowedDir},
+			path:    "/var/run/secrets/kubernetes.io/serviceaccount/token",
+			wantErr: assert.Error,
+		},
+		{
+			name:    "outside-dir",
+			dirs:    []string{allowedDir},
+			path:    filepath.Join(otherDir, "token"),
+			wantErr: assert.Error,
+		},
+		{
+			name:    "path-traversal",
+			dirs:    []string{allowedDir},
+			path:    allowedDir + "/../" + filepath.Base(otherDir) + "/token",
+			wantErr: assert.Error,
+		},
+		{
+			name:    "symlink-outside-dir",
+			dirs:    []string{allowedDir},
+			path:    filepath.Join(allowedDir, "escape"),
+			wantErr: assert.Error,
+		},
+		{
+			name:    "dir-itself",
+			dirs:    []string{allowedDir},
+			path:    allowedDir,
+			wantErr: assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setTokenFileDirs(t, tt.dirs...)
+			tt.wantErr(t, validateTokenFile(tt.path))
+		})
+	}
+
+	t.Run("relative-dir", func(t *testing.T) {
+		assert.Error(t, SetTokenFileDirs([]string{"tokens"}))
+	})
+}
+
+func setTokenFileDirs(t *testing.T, dirs ...string) {
+	t.Helper()
+	require.NoError(t, SetTokenFileDirs(dirs))
+	t.Cleanup(func() {
+		tokenFileDirs = nil
+	})
+}
@@ -0,0 +1,76 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package credentials
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/types"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	secretsv1alpha1 "github.com/hashicorp/vault-secrets-operator/api/v1alpha1"
+)
+
+// TokenCredsKey is the key in the map returned by TokenCredentialProvider.GetCreds
+// that holds the Vault token.
+const TokenCredsKey = "token"
+
+var _ CredentialProvider = (*TokenCredentialProvider)(nil)
+
+type TokenCredentialProvider struct {
+	authObj           *secretsv1alpha1.VaultAuth
+	providerNamespace string
+	uid               types.UID
+}
+
+func (l *TokenCredentialProvider) GetNamespace() string {
+	return l.providerNamespace
+}
+
+func (l *TokenCredentialProvider) GetUID() types.UID {
+	return l.uid
+}
+
+func (l *TokenCredentialProvider) Init(ctx context.Context, client ctrlclient.Client, authObj *secretsv1alpha1.VaultAuth, providerNamespace string) error {
+	l.authObj = authObj
+	l.providerNamespace = providerNamespace
+
+	if l.authObj.Spec.Token == nil ||
+		l.authObj.Spec.Token.SecretKeyRef.Name == "" ||
+		l.authObj.Spec.Token.SecretKeyRef.Key == "" {
+		return fmt.Errorf("token secret key selector is required to " +
+			"retrieve the Vault token")
+	}
+
+	s, err := getSecret(ctx, client, l.providerNamespace, l.authObj.Spec.Token.SecretKeyRef.Name)
+	if err != nil {
+		return err
+	}
+	l.uid = s.ObjectMeta.UID
+
+	return nil
+}
+
+func (l *TokenCredentialProvider) GetCreds(ctx context.Context, client ctrlclient.Client) (map[string]interface{}, error) {
+	logger := log.FromContext(ctx)
+
+	ref := l.authObj.Spec.Token.SecretKeyRef
+	s, err := getSecret(ctx, client, l.providerNamespace, ref.Name)
+	if err != nil {
+		logger.Error(err, "Failed to get the Vault token secret")
+		return nil, err
+	}
+
+	token := strings.TrimSpace(string(s.Data[ref.Key]))
+	if token == "" {
+		return nil, fmt.Errorf("no data found for key %q in secret %s/%s", ref.Key, l.providerNamespace, ref.Name)
+	}
+
+	return map[string]interface{}{
+		TokenCredsKey: token,
+	}, nil
+}
@@ -0,0 +1,158 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package credentials
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	lru "github.com/hashicorp/golang-lru"
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/hashicorp/vault-secrets-operator/internal/metrics"
+)
+
+const (
+	// DefaultTokenRequestReuseFraction is the default fraction of a ServiceAccount token's
+	// lifetime during which it will be reused.
+	DefaultTokenRequestReuseFraction = 0.5
+
+	subsystemTokenRequestCache = "token_request_cache"
+	tokenRequestCacheSize      = 10000
+)
+
+var (
+	tokenRequestCacheHits = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: subsystemTokenRequestCache,
+		Name:      "hits_total",
+		Help:      "ServiceAccount token requests served from the cache",
+	}, []string{metrics.LabelProvider})
+
+	tokenRequestCacheMisses = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metrics.Namespace,
+		Subsystem: subsystemTokenRequestCache,
+		Name:      "misses_total",
+		Help:      "ServiceAccount token requests sent to the Kubernetes API",
+	}, []string{metrics.LabelProvider})
+
+	// tokenRequestCaches holds a tokenRequestCache for each provider that requests
+	// ServiceAccount tokens.
+	tokenRequestCaches = map[string]*tokenRequestCache{
+		ProviderMethodKubernetes: newTokenRequestCache(ProviderMethodKubernetes),
+		ProviderMethodJWT:        newTokenRequestCache(ProviderMethodJWT),
+	}
+)
+
+// MustRegisterMetrics to register the global credential provider Prometheus metrics.
+func MustRegisterMetrics(registry prometheus.Registerer) {
+	registry.MustRegister(
+		tokenRequestCacheHits,
+		tokenRequestCacheMisses,
+	)
+}
+
+// SetTokenRequestReuseFraction sets the fraction of a ServiceAccount token's lifetime
+// during which it will be reused by all providers. A value of 0 disables reuse.
+func SetTokenRequestReuseFraction(f float64) error {
+	if f < 0 || f >= 1 {
+		return fmt.Errorf("invalid token request reuse fraction %v, must be in the range [0, 1)", f)
+	}
+	for _, c := range tokenRequestCaches {
+		c.setReuseFraction(f)
+	}
+	return nil
+}
+
+type tokenRequestCacheEntry struct {
+	token   string
+	reuseBy time.Time
+}
+
+// tokenRequestCache caches the tokens issued by the TokenRequest API, keyed by ServiceAccount UID,
+// audiences, and expiration, so that they can be reused across logins.
+type tokenRequestCache struct {
+	mu            sync.Mutex
+	provider      string
+	cache         *lru.Cache
+	reuseFraction float64
+	now           func() time.Time
+}
+
+func newTokenRequestCache(provider string) *tokenRequestCache {
+	cache, _ := lru.New(tokenRequestCacheSize)
+	return &tokenRequestCache{
+		provider:      provider,
+		cache:         cache,
+		reuseFraction: DefaultTokenRequestReuseFraction,
+		now:           time.Now,
+	}
+}
+
+func (c *tokenRequestCache) setReuseFraction(f float64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.reuseFraction = f
+	c.cache.Purge()
+}
+
+// requestSAToken returns a cached token for the ServiceAccount if one was issued within the
+// reuse window, otherwise it requests a new token via requestSAToken.
+func (c *tokenRequestCache) requestSAToken(ctx context.Context, client ctrlclient.Client, sa *corev1.ServiceAccount, expirationSeconds int64, audiences []string) (string, error) {
+	key := tokenRequestCacheKey(sa, expirationSeconds, audiences)
+
+	c.mu.Lock()
+	reuseFraction := c.reuseFraction
+	if v, ok := c.cache.Get(key); ok {
+		entry := v.(*tokenRequestCacheEntry)
+		if c.now().Before(entry.reuseBy) {
+			c.mu.Unlock()
+			tokenRequestCacheHits.WithLabelValues(c.provider).Inc()
+			return entry.token, nil
+		}
+		c.cache.Remove(key)
+	}
+	c.mu.Unlock()
+
+	tokenRequestCacheMisses.WithLabelValues(c.provider).Inc()
+	issued := c.now()
+	tr, err := requestSAToken(ctx, client, sa, expirationSeconds, audiences)
+	if err != nil {
+		return "", err
+	}
+
+	if reuseFraction > 0 {
+		lifetime := time.Duration(expirationSeconds) * time.Second
+		// the API server may issue a token with a shorter lifetime than requested.
+		if exp := tr.Status.ExpirationTimestamp.Time; !exp.IsZero() && exp.Sub(issued) < lifetime {
+			lifetime = exp.Sub(issued)
+		}
+		c.mu.Lock()
+		c.cache.Add(key, &tokenRequestCacheEntry{
+			token:   tr.Status.Token,
+			reuseBy: issued.Add(time.Duration(float64(lifetime) * reuseFraction)),
+		})
+		c.mu.Unlock()
+	}
+
+	return tr.Status.Token, nil
+}
+
+func tokenRequestCacheKey(sa *corev1.ServiceAccount, expirationSeconds int64, audiences []string) string {
+	aud := make([]string, len(audiences))
+	copy(aud, audiences)
+	sort.Strings(aud)
+	return strings.Join([]string{
+		string(sa.UID),
+		strconv.FormatInt(expirationSeconds, 10),
+		strings.Join(aud, ","),
+	}, ":")
+}
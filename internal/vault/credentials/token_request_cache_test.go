@@ -0,0 +1,182 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package credentials
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	authv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// tokenRequestClient stands in for the TokenRequest API, which is not supported
+// by the fake client.
+type tokenRequestClient struct {
+	ctrlclient.Client
+	requests int
+	// maxExpirationSeconds caps the lifetime of issued tokens, when set.
+	maxExpirationSeconds int64
+	now                  func() time.Time
+}
+
+func (c *tokenRequestClient) SubResource(subResource string) ctrlclient.SubResourceClient {
+	return &tokenRequestSubResourceClient{
+		SubResourceClient: c.Client.SubResource(subResource),
+		client:            c,
+	}
+}
+
+type tokenRequestSubResourceClient struct {
+	ctrlclient.SubResourceClient
+	client *tokenRequestClient
+}
+
+func (c *tokenRequestSubResourceClient) Create(_ context.Context, obj ctrlclient.Object, subResource ctrlclient.Object, _ ...ctrlclient.SubResourceCreateOption) error {
+	c.client.requests++
+	tr := subResource.(*authv1.TokenRequest)
+	exp := *tr.Spec.ExpirationSeconds
+	if c.client.maxExpirationSeconds > 0 && exp > c.client.maxExpirationSeconds {
+		exp = c.client.maxExpirationSeconds
+	}
+	tr.Status.Token = fmt.Sprintf("%s-%d", obj.GetName(), c.client.requests)
+	tr.Status.ExpirationTimestamp = metav1.NewTime(c.client.now().Add(time.Duration(exp) * time.Second))
+	return nil
+}
+
+func Test_tokenRequestCache_requestSAToken(t *testing.T) {
+	ctx := context.Background()
+	sa := &corev1.ServiceAccount{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "sa-1",
+			Namespace: "tenant-1",
+			UID:       "sa-1-uid",
+		},
+	}
+	type request struct {
+		after     time.Duration
+		sa        *corev1.ServiceAccount
+		audiences []string
+		want      string
+	}
+	tests := []struct {
+		name                 string
+		reuseFraction        float64
+		maxExpirationSeconds int64
+		requests             []request
+		wantHits             float64
+		wantMisses           float64
+	}{
+		{
+			name:          "reused-within-window",
+			reuseFraction: 0.5,
+			requests: []request{
+				{sa: sa, want: "sa-1-1"},
+				{after: time.Minute, sa: sa, want: "sa-1-1"},
+				{after: time.Minute, sa: sa, audiences: []string{"vault"}, want: "sa-1-2"},
+				{after: time.Minute, sa: sa, audiences: []string{"vault"}, want: "sa-1-2"},
+			},
+			wantHits:   2,
+			wantMisses: 2,
+		},
+		{
+			name:          "renewed-after-window",
+			reuseFraction: 0.5,
+			requests: []request{
+				{sa: sa, want: "sa-1-1"},
+				{after: 299 * time.Second, sa: sa, want: "sa-1-1"},
+				{after: time.Second, sa: sa, want: "sa-1-2"},
+			},
+			wantHits:   1,
+			wantMisses: 2,
+		},
+		{
+			name:                 "capped-expiration",
+			reuseFraction:        0.5,
+			maxExpirationSeconds: 60,
+			requests: []request{
+				{sa: sa, want: "sa-1-1"},
+				{after: 29 * time.Second, sa: sa, want: "sa-1-1"},
+				{after: time.Second, sa: sa, want: "sa-1-2"},
+			},
+			wantHits:   1,
+			wantMisses: 2,
+		},
+		{
+			name:          "new-service-account-uid",
+			reuseFraction: 0.5,
+			requests: []request{
+				{sa: sa, want: "sa-1-1"},
+				{
+					sa: &corev1.ServiceAccount{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      "sa-1",
+							Namespace: "tenant-1",
+							UID:       "sa-1-uid-new",
+						},
+					},
+					want: "sa-1-2",
+				},
+			},
+			wantMisses: 2,
+		},
+		{
+			name:          "disabled",
+			reuseFraction: 0,
+			requests: []request{
+				{sa: sa, want: "sa-1-1"},
+				{sa: sa, want: "sa-1-2"},
+			},
+			wantMisses: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := "test-" + tt.name
+			now := time.Unix(1680000000, 0)
+			nowFunc := func() time.Time { return now }
+			client := &tokenRequestClient{
+				Client:               fake.NewClientBuilder().Build(),
+				maxExpirationSeconds: tt.maxExpirationSeconds,
+				now:                  nowFunc,
+			}
+
+			c := newTokenRequestCache(provider)
+			c.now = nowFunc
+			c.setReuseFraction(tt.reuseFraction)
+
+			for _, r := range tt.requests {
+				now = now.Add(r.after)
+				got, err := c.requestSAToken(ctx, client, r.sa, 600, r.audiences)
+				require.NoError(t, err)
+				assert.Equal(t, r.want, got)
+			}
+
+			assert.Equal(t, tt.wantHits, testutil.ToFloat64(tokenRequestCacheHits.WithLabelValues(provider)))
+			assert.Equal(t, tt.wantMisses, testutil.ToFloat64(tokenRequestCacheMisses.WithLabelValues(provider)))
+		})
+	}
+}
+
+func TestSetTokenRequestReuseFraction(t *testing.T) {
+	t.Cleanup(func() {
+		require.NoError(t, SetTokenRequestReuseFraction(DefaultTokenRequestReuseFraction))
+	})
+
+	assert.NoError(t, SetTokenRequestReuseFraction(0))
+	assert.NoError(t, SetTokenRequestReuseFraction(0.8))
+	assert.Error(t, SetTokenRequestReuseFraction(1))
+	assert.Error(t, SetTokenRequestReuseFraction(-0.1))
+	for _, c := range tokenRequestCaches {
+		assert.Equal(t, 0.8, c.reuseFraction)
+	}
+}
@@ -0,0 +1,89 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package credentials
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	secretsv1alpha1 "github.com/hashicorp/vault-secrets-operator/api/v1alpha1"
+)
+
+// UsernameCredsKey is the key in the map returned by UserPassCredentialProvider.GetCreds
+// that holds the username. The username is part of the login path for the userpass and ldap methods.
+const UsernameCredsKey = "username"
+
+var _ CredentialProvider = (*UserPassCredentialProvider)(nil)
+
+// UserPassCredentialProvider provides credentials for both the userpass and ldap methods.
+type UserPassCredentialProvider struct {
+	authObj           *secretsv1alpha1.VaultAuth
+	providerNamespace string
+	credsSecret       *corev1.Secret
+	uid               types.UID
+}
+
+func (l *UserPassCredentialProvider) GetNamespace() string {
+	return l.providerNamespace
+}
+
+func (l *UserPassCredentialProvider) GetUID() types.UID {
+	return l.uid
+}
+
+func (l *UserPassCredentialProvider) Init(ctx context.Context, client ctrlclient.Client, authObj *secretsv1alpha1.VaultAuth, providerNamespace string) error {
+	l.authObj = authObj
+	l.providerNamespace = providerNamespace
+
+	config := l.config()
+	if config == nil || config.SecretRef == "" {
+		return fmt.Errorf("a credentials secret reference is required to "+
+			"authenticate to Vault's %s authentication backend", l.authObj.Spec.Method)
+	}
+
+	var err error
+	l.credsSecret, err = getSecret(ctx, client, l.providerNamespace, config.SecretRef)
+	if err != nil {
+		return err
+	}
+	l.uid = l.credsSecret.ObjectMeta.UID
+
+	return nil
+}
+
+func (l *UserPassCredentialProvider) config() *secretsv1alpha1.VaultAuthConfigUserPass {
+	if l.authObj.Spec.Method == ProviderMethodLDAP {
+		return l.authObj.Spec.LDAP
+	}
+	return l.authObj.Spec.UserPass
+}
+
+func (l *UserPassCredentialProvider) GetCreds(ctx context.Context, client ctrlclient.Client) (map[string]interface{}, error) {
+	logger := log.FromContext(ctx)
+
+	var err error
+	l.credsSecret, err = getSecret(ctx, client, l.providerNamespace, l.config().SecretRef)
+	if err != nil {
+		logger.Error(err, "Failed to get the credentials secret")
+		return nil, err
+	}
+
+	username := string(l.credsSecret.Data[corev1.BasicAuthUsernameKey])
+	password := string(l.credsSecret.Data[corev1.BasicAuthPasswordKey])
+	if username == "" || password == "" {
+		return nil, fmt.Errorf("secret %s/%s must contain the keys %q and %q",
+			l.providerNamespace, l.credsSecret.Name, corev1.BasicAuthUsernameKey, corev1.BasicAuthPasswordKey)
+	}
+
+	// credentials needed for userpass and ldap auth
+	return map[string]interface{}{
+		UsernameCredsKey: username,
+		"password":       password,
+	}, nil
+}
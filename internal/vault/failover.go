@@ -0,0 +1,181 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package vault
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"sync"
+	"time"
+
+	secretsv1alpha1 "github.com/hashicorp/vault-secrets-operator/api/v1alpha1"
+)
+
+const (
+	failoverHealthCheckTimeout = 5 * time.Second
+	// failbackInterval is the minimum amount of time between checks for whether a
+	// more preferred address has become healthy again.
+	failbackInterval = 30 * time.Second
+)
+
+// ConnectionAddresses returns all of the VaultConnection's addresses in order of preference.
+func ConnectionAddresses(spec secretsv1alpha1.VaultConnectionSpec) []string {
+	var result []string
+	if spec.Address != "" {
+		result = append(result, spec.Address)
+	}
+
+	addrs := make([]secretsv1alpha1.VaultConnectionAddress, len(spec.Addresses))
+	copy(addrs, spec.Addresses)
+	sort.SliceStable(addrs, func(i, j int) bool {
+		return addrs[i].Weight > addrs[j].Weight
+	})
+	for _, a := range addrs {
+		result = append(result, a.Address)
+	}
+
+	return result
+}
+
+var _ http.RoundTripper = (*failoverTransport)(nil)
+
+// failoverTransport sends all requests to the currently active Vault address.
+// The next healthy address becomes active whenever a request to the active address fails,
+// or when it responds as being sealed. Since the failed request is returned to the caller,
+// retrying it is left to the Vault client.
+type failoverTransport struct {
+	base      http.RoundTripper
+	addresses []*url.URL
+	mu        sync.Mutex
+	active    int
+	checking  bool
+	lastCheck time.Time
+	now       func() time.Time
+	// perfStandbyOK considers performance standbys to be healthy.
+	perfStandbyOK bool
+}
+
+func newFailoverTransport(base http.RoundTripper, addresses []string) (*failoverTransport, error) {
+	t := &failoverTransport{
+		base: base,
+		now:  time.Now,
+	}
+	for _, addr := range addresses {
+		u, err := url.Parse(addr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid Vault address %q: %w", addr, err)
+		}
+		t.addresses = append(t.addresses, u)
+	}
+	if len(t.addresses) == 0 {
+		return nil, fmt.Errorf("at least one Vault address is required")
+	}
+
+	return t, nil
+}
+
+func (t *failoverTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	idx, addr := t.activeAddress()
+
+	r := req.Clone(req.Context())
+	r.URL.Scheme = addr.Scheme
+	r.URL.Host = addr.Host
+	r.Host = ""
+
+	resp, err := t.base.RoundTrip(r)
+	if err != nil || resp.StatusCode == http.StatusServiceUnavailable {
+		t.failover(idx)
+	}
+
+	return resp, err
+}
+
+// CloseIdleConnections closes the idle connections of the base transport.
+func (t *failoverTransport) CloseIdleConnections() {
+	type closeIdler interface {
+		CloseIdleConnections()
+	}
+	if c, ok := t.base.(closeIdler); ok {
+		c.CloseIdleConnections()
+	}
+}
+
+// activeAddress returns the active address, and starts a failback check in the background
+// when a less preferred address has been active for at least the failbackInterval.
+func (t *failoverTransport) activeAddress() (int, *url.URL) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.active != 0 && !t.checking && t.now().Sub(t.lastCheck) >= failbackInterval {
+		t.checking = true
+		go t.selectAddress(-1)
+	}
+
+	return t.active, t.addresses[t.active]
+}
+
+// failover to the next healthy address, if the failed address is still active.
+func (t *failoverTransport) failover(failed int) {
+	t.mu.Lock()
+	if t.active != failed || t.checking {
+		t.mu.Unlock()
+		return
+	}
+	t.checking = true
+	t.mu.Unlock()
+
+	t.selectAddress(failed)
+}
+
+// selectAddress activates the most preferred healthy address, skipping the failed address.
+// If there are no healthy addresses, the address following the failed one becomes active.
+func (t *failoverTransport) selectAddress(failed int) {
+	next := -1
+	for i, addr := range t.addresses {
+		if i != failed && t.isHealthy(addr) {
+			next = i
+			break
+		}
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	switch {
+	case next >= 0:
+		t.active = next
+	case failed >= 0:
+		t.active = (failed + 1) % len(t.addresses)
+	}
+	t.lastCheck = t.now()
+	t.checking = false
+}
+
+// isHealthy returns true if sys/health reports that the Vault server at addr
+// is initialized, unsealed, and active, or a performance standby if perfStandbyOK is set.
+func (t *failoverTransport) isHealthy(addr *url.URL) bool {
+	ctx, cancel := context.WithTimeout(context.Background(), failoverHealthCheckTimeout)
+	defer cancel()
+
+	u := addr.JoinPath("/v1/sys/health")
+	if t.perfStandbyOK {
+		u.RawQuery = "perfstandbyok=true"
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return false
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return false
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	return resp.StatusCode == http.StatusOK
+}
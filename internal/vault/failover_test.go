@@ -0,0 +1,171 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package vault
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	secretsv1alpha1 "github.com/hashicorp/vault-secrets-operator/api/v1alpha1"
+)
+
+func TestConnectionAddresses(t *testing.T) {
+	tests := []struct {
+		name string
+		spec secretsv1alpha1.VaultConnectionSpec
+		want []string
+	}{
+		{
+			name: "address",
+			spec: secretsv1alpha1.VaultConnectionSpec{
+				Address: "https://vault-0",
+			},
+			want: []string{"https://vault-0"},
+		},
+		{
+			name: "ordered",
+			spec: secretsv1alpha1.VaultConnectionSpec{
+				Addresses: []secretsv1alpha1.VaultConnectionAddress{
+					{Address: "https://vault-1"},
+					{Address: "https://vault-2"},
+				},
+			},
+			want: []string{"https://vault-1", "https://vault-2"},
+		},
+		{
+			name: "weighted",
+			spec: secretsv1alpha1.VaultConnectionSpec{
+				Address: "https://vault-0",
+				Addresses: []secretsv1alpha1.VaultConnectionAddress{
+					{Address: "https://vault-1", Weight: 1},
+					{Address: "https://vault-2", Weight: 10},
+					{Address: "https://vault-3", Weight: 1},
+				},
+			},
+			want: []string{"https://vault-0", "https://vault-2", "https://vault-1", "https://vault-3"},
+		},
+		{
+			name: "none",
+			spec: secretsv1alpha1.VaultConnectionSpec{},
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ConnectionAddresses(tt.spec))
+		})
+	}
+}
+
+// vaultServer stands in for a Vault server, its sys/health status can be changed at any time.
+type vaultServer struct {
+	*httptest.Server
+	name   string
+	status atomic.Int32
+}
+
+func newVaultServer(t *testing.T, name string) *vaultServer {
+	t.Helper()
+	s := &vaultServer{name: name}
+	s.status.Store(http.StatusOK)
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		status := int(s.status.Load())
+		if r.URL.Path == "/v1/sys/health" {
+			w.WriteHeader(status)
+			return
+		}
+		if status == http.StatusServiceUnavailable {
+			w.WriteHeader(status)
+			fmt.Fprint(w, `{"errors":["Vault is sealed"]}`)
+			return
+		}
+		fmt.Fprintf(w, `{"data":{"server":%q}}`, s.name)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func TestMakeVaultClient_failover(t *testing.T) {
+	ctx := context.Background()
+
+	primary := newVaultServer(t, "primary")
+	secondary := newVaultServer(t, "secondary")
+	tertiary := newVaultServer(t, "tertiary")
+
+	c, err := MakeVaultClient(ctx, &ClientConfig{
+		Addresses: []string{primary.URL, secondary.URL, tertiary.URL},
+	}, fake.NewClientBuilder().Build())
+	require.NoError(t, err)
+	c.SetMinRetryWait(time.Millisecond)
+	c.SetMaxRetryWait(time.Millisecond)
+
+	transport, ok := c.CloneConfig().HttpClient.Transport.(*failoverTransport)
+	require.True(t, ok)
+	var now atomic.Int64
+	now.Store(time.Now().Unix())
+	transport.now = func() time.Time {
+		return time.Unix(now.Load(), 0)
+	}
+
+	assertServer := func(want string) {
+		t.Helper()
+		resp, err := c.Logical().ReadWithContext(ctx, "secret/foo")
+		require.NoError(t, err)
+		require.NotNil(t, resp)
+		assert.Equal(t, want, resp.Data["server"])
+	}
+
+	assertServer("primary")
+
+	// fails over when sealed, standby nodes are skipped.
+	primary.status.Store(http.StatusServiceUnavailable)
+	secondary.status.Store(http.StatusTooManyRequests)
+	assertServer("tertiary")
+
+	// fails over when the connection drops.
+	secondary.status.Store(http.StatusOK)
+	tertiary.Close()
+	assertServer("secondary")
+
+	// does not fail back before the failback interval has elapsed.
+	primary.status.Store(http.StatusOK)
+	assertServer("secondary")
+
+	// fails back to the most preferred healthy address in the background.
+	now.Add(int64(failbackInterval / time.Second))
+	assertServer("secondary")
+	assert.Eventually(t, func() bool {
+		idx, _ := transport.activeAddress()
+		return idx == 0
+	}, 5*time.Second, 10*time.Millisecond)
+	assertServer("primary")
+}
+
+func Test_failoverTransport_isHealthy_perfStandbyOK(t *testing.T) {
+	// a performance standby is only reported as healthy when perfstandbyok is set.
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("perfstandbyok") == "true" {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		w.WriteHeader(473)
+	}))
+	t.Cleanup(server.Close)
+
+	transport, err := newFailoverTransport(http.DefaultTransport, []string{server.URL})
+	require.NoError(t, err)
+	assert.False(t, transport.isHealthy(transport.addresses[0]))
+
+	transport.perfStandbyOK = true
+	assert.True(t, transport.isHealthy(transport.addresses[0]))
+}
@@ -0,0 +1,141 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package vault
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/vault/api"
+
+	"github.com/hashicorp/vault-secrets-operator/internal/consts"
+)
+
+// ReadKVSecret reads the secret name from the KV secrets engine of kvType mounted at mount.
+// A version greater than zero selects that version of a kv-v2 secret, otherwise the latest version
+// is read. The secret is read through c, so that the request is subject to the VaultConnection's
+// rate limits, and so that a request that is held by a control group returns a ControlGroupError.
+// api.ErrSecretNotFound is returned if the secret does not exist.
+func ReadKVSecret(ctx context.Context, c Client, kvType, mount, name string, version int) (*api.KVSecret, error) {
+	var p string
+	var resp *api.Secret
+	var err error
+	switch kvType {
+	case consts.KVSecretTypeV1:
+		if version > 0 {
+			return nil, fmt.Errorf("version is not supported for secret type %q", kvType)
+		}
+		p = fmt.Sprintf("%s/%s", mount, name)
+		resp, err = c.Read(ctx, p)
+	case consts.KVSecretTypeV2:
+		p = fmt.Sprintf("%s/data/%s", mount, name)
+		if version > 0 {
+			resp, err = c.ReadWithData(ctx, p, map[string][]string{
+				"version": {strconv.Itoa(version)},
+			})
+		} else {
+			resp, err = c.Read(ctx, p)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported secret type %q", kvType)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if resp == nil {
+		return nil, fmt.Errorf("%w: at %s", api.ErrSecretNotFound, p)
+	}
+
+	s, err := NewKVSecret(kvType, resp)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing secret at %s: %w", p, err)
+	}
+	return s, nil
+}
+
+// NewKVSecret returns the KVSecret for the response of a read from a KV secrets engine of kvType.
+// The Data of a kv-v2 secret is nil if its version has been deleted.
+func NewKVSecret(kvType string, resp *api.Secret) (*api.KVSecret, error) {
+	switch kvType {
+	case consts.KVSecretTypeV1:
+		return &api.KVSecret{
+			Data: resp.Data,
+			Raw:  resp,
+		}, nil
+	case consts.KVSecretTypeV2:
+	default:
+		return nil, fmt.Errorf("unsupported secret type %q", kvType)
+	}
+
+	s := &api.KVSecret{
+		Raw: resp,
+	}
+	if resp.Data == nil {
+		return s, nil
+	}
+
+	d, ok := resp.Data["data"]
+	if !ok {
+		return nil, fmt.Errorf("missing expected 'data' element")
+	}
+	if d != nil {
+		if s.Data, ok = d.(map[string]any); !ok {
+			return nil, fmt.Errorf("unexpected type for 'data' element: %T", d)
+		}
+	}
+
+	m, ok := resp.Data["metadata"].(map[string]any)
+	if !ok {
+		return s, nil
+	}
+	s.CustomMetadata, _ = m["custom_metadata"].(map[string]any)
+
+	var err error
+	s.VersionMetadata, err = newKVVersionMetadata(m)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get version metadata: %w", err)
+	}
+
+	return s, nil
+}
+
+func newKVVersionMetadata(m map[string]any) (*api.KVVersionMetadata, error) {
+	v := &api.KVVersionMetadata{}
+	switch n := m["version"].(type) {
+	case json.Number:
+		i, err := n.Int64()
+		if err != nil {
+			return nil, fmt.Errorf("invalid version %q: %w", n, err)
+		}
+		v.Version = int(i)
+	case float64:
+		v.Version = int(n)
+	case nil:
+	default:
+		return nil, fmt.Errorf("unexpected type for version: %T", n)
+	}
+
+	var err error
+	if v.CreatedTime, err = parseKVTime(m["created_time"]); err != nil {
+		return nil, fmt.Errorf("invalid created_time: %w", err)
+	}
+	if v.DeletionTime, err = parseKVTime(m["deletion_time"]); err != nil {
+		return nil, fmt.Errorf("invalid deletion_time: %w", err)
+	}
+	v.Destroyed, _ = m["destroyed"].(bool)
+
+	return v, nil
+}
+
+// parseKVTime parses an RFC3339 timestamp, the empty string is the zero time.
+func parseKVTime(v any) (time.Time, error) {
+	s, _ := v.(string)
+	if s == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(time.RFC3339, s)
+}
@@ -0,0 +1,153 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package vault
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/vault/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	secretsv1alpha1 "github.com/hashicorp/vault-secrets-operator/api/v1alpha1"
+	"github.com/hashicorp/vault-secrets-operator/internal/consts"
+)
+
+func TestReadKVSecret(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path + "?" + r.URL.RawQuery {
+		case "/v1/kv1/app?":
+			fmt.Fprint(w, `{"data":{"username":"user-1"}}`)
+		case "/v1/kv/data/app?":
+			fmt.Fprint(w, `{"data":{"data":{"username":"user-2"},"metadata":{"version":2,`+
+				`"created_time":"2023-01-02T00:00:00Z","deletion_time":"","destroyed":false,`+
+				`"custom_metadata":{"owner":"team-1"}}}}`)
+		case "/v1/kv/data/app?version=1":
+			// a deleted version is returned with a 404.
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"data":{"data":null,"metadata":{"version":1,`+
+				`"created_time":"2023-01-01T00:00:00Z","deletion_time":"2023-01-03T00:00:00Z","destroyed":false,`+
+				`"custom_metadata":null}}}`)
+		case "/v1/kv/data/held?":
+			fmt.Fprint(w, `{"wrap_info":{"token":"wrapping-token","accessor":"accessor-1","ttl":3600}}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	config := api.DefaultConfig()
+	config.Address = server.URL
+	config.MaxRetries = 0
+	vc, err := api.NewClient(config)
+	require.NoError(t, err)
+	vc.SetToken("client-token")
+
+	c := &defaultClient{
+		client:  vc,
+		connObj: &secretsv1alpha1.VaultConnection{},
+	}
+
+	tests := []struct {
+		name    string
+		kvType  string
+		mount   string
+		secret  string
+		version int
+		want    *api.KVSecret
+		wantErr assert.ErrorAssertionFunc
+	}{
+		{
+			name:   "kv-v1",
+			kvType: consts.KVSecretTypeV1,
+			mount:  "kv1",
+			secret: "app",
+			want: &api.KVSecret{
+				Data: map[string]any{"username": "user-1"},
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name:   "kv-v2",
+			kvType: consts.KVSecretTypeV2,
+			mount:  "kv",
+			secret: "app",
+			want: &api.KVSecret{
+				Data: map[string]any{"username": "user-2"},
+				VersionMetadata: &api.KVVersionMetadata{
+					Version:     2,
+					CreatedTime: time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC),
+				},
+				CustomMetadata: map[string]any{"owner": "team-1"},
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name:    "kv-v2-deleted-version",
+			kvType:  consts.KVSecretTypeV2,
+			mount:   "kv",
+			secret:  "app",
+			version: 1,
+			want: &api.KVSecret{
+				VersionMetadata: &api.KVVersionMetadata{
+					Version:      1,
+					CreatedTime:  time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
+					DeletionTime: time.Date(2023, 1, 3, 0, 0, 0, 0, time.UTC),
+				},
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name:   "not-found",
+			kvType: consts.KVSecretTypeV2,
+			mount:  "kv",
+			secret: "other",
+			wantErr: func(t assert.TestingT, err error, i ...interface{}) bool {
+				return assert.ErrorIs(t, err, api.ErrSecretNotFound, i...)
+			},
+		},
+		{
+			name:   "control-group",
+			kvType: consts.KVSecretTypeV2,
+			mount:  "kv",
+			secret: "held",
+			wantErr: func(t assert.TestingT, err error, i ...interface{}) bool {
+				var cgErr *ControlGroupError
+				return assert.ErrorAs(t, err, &cgErr, i...) &&
+					assert.Equal(t, "kv/data/held", cgErr.Path, i...)
+			},
+		},
+		{
+			name:    "kv-v1-version",
+			kvType:  consts.KVSecretTypeV1,
+			mount:   "kv1",
+			secret:  "app",
+			version: 1,
+			wantErr: assert.Error,
+		},
+		{
+			name:    "unsupported-type",
+			kvType:  "kv-v3",
+			mount:   "kv",
+			secret:  "app",
+			wantErr: assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ReadKVSecret(context.Background(), c, tt.kvType, tt.mount, tt.secret, tt.version)
+			if !tt.wantErr(t, err, "ReadKVSecret()") || err != nil {
+				return
+			}
+			require.NotNil(t, got.Raw)
+			got.Raw = nil
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,136 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package vault
+
+import (
+	"context"
+	"net/http"
+	"sync"
+
+	"golang.org/x/time/rate"
+	"k8s.io/apimachinery/pkg/types"
+
+	secretsv1alpha1 "github.com/hashicorp/vault-secrets-operator/api/v1alpha1"
+)
+
+// connectionLimiters holds the connectionLimiter of every VaultConnection,
+// so that the limits are shared by all of a VaultConnection's Clients and their clones.
+var connectionLimiters = &connectionLimiterRegistry{
+	limiters: make(map[types.UID]*connectionLimiter),
+}
+
+type connectionLimiterRegistry struct {
+	mu       sync.Mutex
+	limiters map[types.UID]*connectionLimiter
+}
+
+// get returns the connectionLimiter for connObj. A new connectionLimiter
+// replaces the existing one whenever the VaultConnection's limits change.
+func (r *connectionLimiterRegistry) get(connObj *secretsv1alpha1.VaultConnection) *connectionLimiter {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var rateLimit secretsv1alpha1.VaultConnectionRateLimit
+	if connObj.Spec.RateLimit != nil {
+		rateLimit = *connObj.Spec.RateLimit
+	}
+
+	if l, ok := r.limiters[connObj.UID]; ok &&
+		l.rateLimit == rateLimit && l.maxInFlight == connObj.Spec.MaxInFlight {
+		return l
+	}
+
+	l := newConnectionLimiter(rateLimit, connObj.Spec.MaxInFlight)
+	r.limiters[connObj.UID] = l
+	return l
+}
+
+func (r *connectionLimiterRegistry) delete(uid types.UID) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.limiters, uid)
+}
+
+// DeleteConnectionLimiter removes the rate limit and max in-flight state of
+// connObj. It should be called once the VaultConnection has been deleted.
+func DeleteConnectionLimiter(connObj *secretsv1alpha1.VaultConnection) {
+	connectionLimiters.delete(connObj.UID)
+}
+
+// connectionLimiter throttles the requests sent to Vault for a single VaultConnection.
+type connectionLimiter struct {
+	rateLimit   secretsv1alpha1.VaultConnectionRateLimit
+	maxInFlight int
+	limiter     *rate.Limiter
+	inFlight    chan struct{}
+}
+
+func newConnectionLimiter(rateLimit secretsv1alpha1.VaultConnectionRateLimit, maxInFlight int) *connectionLimiter {
+	l := &connectionLimiter{
+		rateLimit:   rateLimit,
+		maxInFlight: maxInFlight,
+	}
+
+	if rateLimit.QPS > 0 {
+		burst := rateLimit.Burst
+		if burst == 0 {
+			burst = rateLimit.QPS
+		}
+		l.limiter = rate.NewLimiter(rate.Limit(rateLimit.QPS), burst)
+	}
+
+	if maxInFlight > 0 {
+		l.inFlight = make(chan struct{}, maxInFlight)
+	}
+
+	return l
+}
+
+// wait blocks until a request is allowed to be sent, or ctx is done. Upon success,
+// the returned func must be called once the request has completed.
+func (l *connectionLimiter) wait(ctx context.Context) (func(), error) {
+	if l.limiter != nil {
+		if err := l.limiter.Wait(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	if l.inFlight == nil {
+		return func() {}, nil
+	}
+
+	select {
+	case l.inFlight <- struct{}{}:
+		return func() { <-l.inFlight }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+var _ http.RoundTripper = (*throttledTransport)(nil)
+
+// throttledTransport throttles the requests that a Vault client sends on its own,
+// e.g. the token renewals of an api.LifetimeWatcher.
+type throttledTransport struct {
+	base     http.RoundTripper
+	throttle func(context.Context) (func(), error)
+}
+
+func (t *throttledTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	release, err := t.throttle(req.Context())
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	return t.base.RoundTrip(req)
+}
+
+// CloseIdleConnections closes the idle connections of the base transport.
+func (t *throttledTransport) CloseIdleConnections() {
+	if c, ok := t.base.(interface{ CloseIdleConnections() }); ok {
+		c.CloseIdleConnections()
+	}
+}
@@ -0,0 +1,258 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package vault
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/vault/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	secretsv1alpha1 "github.com/hashicorp/vault-secrets-operator/api/v1alpha1"
+	"github.com/hashicorp/vault-secrets-operator/internal/consts"
+)
+
+func Test_connectionLimiterRegistry_get(t *testing.T) {
+	r := &connectionLimiterRegistry{
+		limiters: make(map[types.UID]*connectionLimiter),
+	}
+	connObj := &secretsv1alpha1.VaultConnection{
+		ObjectMeta: v1.ObjectMeta{
+			UID: "conn-1",
+		},
+		Spec: secretsv1alpha1.VaultConnectionSpec{
+			RateLimit: &secretsv1alpha1.VaultConnectionRateLimit{
+				QPS: 10,
+			},
+			MaxInFlight: 5,
+		},
+	}
+
+	l := r.get(connObj)
+	require.NotNil(t, l.limiter)
+	assert.Equal(t, 10, l.limiter.Burst())
+	assert.Equal(t, 5, cap(l.inFlight))
+	assert.Same(t, l, r.get(connObj.DeepCopy()), "expected the limiter to be shared")
+
+	updated := connObj.DeepCopy()
+	updated.Spec.RateLimit.Burst = 20
+	assert.NotSame(t, l, r.get(updated), "expected a new limiter after the limits changed")
+	assert.Equal(t, 20, r.get(updated).limiter.Burst())
+
+	other := &secretsv1alpha1.VaultConnection{
+		ObjectMeta: v1.ObjectMeta{
+			UID: "conn-2",
+		},
+	}
+	unlimited := r.get(other)
+	assert.Nil(t, unlimited.limiter)
+	assert.Nil(t, unlimited.inFlight)
+
+	r.delete(connObj.UID)
+	assert.Len(t, r.limiters, 1)
+}
+
+func Test_connectionLimiter_wait(t *testing.T) {
+	t.Run("max-in-flight", func(t *testing.T) {
+		l := newConnectionLimiter(secretsv1alpha1.VaultConnectionRateLimit{}, 1)
+		release, err := l.wait(context.Background())
+		require.NoError(t, err)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		_, err = l.wait(ctx)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+		release()
+		release, err = l.wait(context.Background())
+		require.NoError(t, err)
+		release()
+	})
+	t.Run("rate-limit", func(t *testing.T) {
+		l := newConnectionLimiter(secretsv1alpha1.VaultConnectionRateLimit{
+			QPS:   1,
+			Burst: 2,
+		}, 0)
+		for i := 0; i < 2; i++ {
+			release, err := l.wait(context.Background())
+			require.NoError(t, err)
+			release()
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		_, err := l.wait(ctx)
+		assert.Error(t, err, "expected the burst to be exhausted")
+	})
+}
+
+func Test_defaultClient_Read_maxInFlight(t *testing.T) {
+	var inFlight, maxSeen int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			m := atomic.LoadInt32(&maxSeen)
+			if n <= m || atomic.CompareAndSwapInt32(&maxSeen, m, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		fmt.Fprint(w, `{"data":{"foo":"bar"}}`)
+	}))
+	t.Cleanup(server.Close)
+
+	connObj := &secretsv1alpha1.VaultConnection{
+		ObjectMeta: v1.ObjectMeta{
+			Name:      "default",
+			Namespace: "vault",
+		},
+		Spec: secretsv1alpha1.VaultConnectionSpec{
+			MaxInFlight: 2,
+		},
+	}
+	config := api.DefaultConfig()
+	config.Address = server.URL
+	vc, err := api.NewClient(config)
+	require.NoError(t, err)
+
+	c := &defaultClient{
+		client:  vc,
+		connObj: connObj,
+		limiter: newConnectionLimiter(secretsv1alpha1.VaultConnectionRateLimit{}, connObj.Spec.MaxInFlight),
+	}
+	clone, err := c.Clone("tenant-1")
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(client Client) {
+			defer wg.Done()
+			_, err := client.Read(context.Background(), "secret/foo")
+			assert.NoError(t, err)
+		}([]Client{c, clone}[i%2])
+	}
+	wg.Wait()
+
+	assert.LessOrEqual(t, atomic.LoadInt32(&maxSeen), int32(2),
+		"expected the clone to share the max in-flight requests")
+}
+
+func Test_defaultClient_Clone_limiter(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"data":{"data":{"foo":"bar"}}}`)
+	}))
+	t.Cleanup(server.Close)
+
+	config := api.DefaultConfig()
+	config.Address = server.URL
+	vc, err := api.NewClient(config)
+	require.NoError(t, err)
+
+	c := &defaultClient{
+		client:  vc,
+		connObj: &secretsv1alpha1.VaultConnection{},
+		limiter: newConnectionLimiter(secretsv1alpha1.VaultConnectionRateLimit{
+			QPS:   1,
+			Burst: 1,
+		}, 0),
+	}
+	clone, err := c.Clone("tenant-1")
+	require.NoError(t, err)
+	require.IsType(t, &defaultClient{}, clone)
+	assert.Same(t, c.limiter, clone.(*defaultClient).limiter)
+
+	// the parent exhausts the burst, so the clone's KV read is throttled.
+	_, err = ReadKVSecret(context.Background(), c, consts.KVSecretTypeV2, "kv", "app", 0)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err = ReadKVSecret(ctx, clone, consts.KVSecretTypeV2, "kv", "app", 0)
+	assert.Error(t, err, "expected the clone to share the parent's rate limit")
+}
+
+func Test_defaultClient_throttledRequests(t *testing.T) {
+	var mu sync.Mutex
+	var namespaces []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		namespaces = append(namespaces, r.Header.Get("X-Vault-Namespace"))
+		mu.Unlock()
+		switch r.URL.Path {
+		case "/v1/sys/wrapping/unwrap":
+			fmt.Fprint(w, `{"data":{"secret_id":"secret-id"}}`)
+		case "/v1/auth/token/renew-self":
+			fmt.Fprint(w, `{"auth":{"client_token":"client-token","renewable":true,"lease_duration":3600}}`)
+		default:
+			fmt.Fprint(w, `{"data":{"foo":"bar"}}`)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	newClient := func(t *testing.T) *defaultClient {
+		t.Helper()
+
+		config := api.DefaultConfig()
+		config.Address = server.URL
+		vc, err := api.NewClient(config)
+		require.NoError(t, err)
+		vc.SetToken("client-token")
+		vc.SetNamespace("tenant-1")
+
+		return &defaultClient{
+			client:  vc,
+			connObj: &secretsv1alpha1.VaultConnection{},
+			limiter: newConnectionLimiter(secretsv1alpha1.VaultConnectionRateLimit{
+				QPS:   1,
+				Burst: 1,
+			}, 0),
+		}
+	}
+
+	tests := map[string]func(context.Context, *defaultClient) error{
+		"unwrap": func(ctx context.Context, c *defaultClient) error {
+			_, err := c.unwrap(ctx, "wrapping-token")
+			return err
+		},
+		"renew": func(ctx context.Context, c *defaultClient) error {
+			vc, err := c.newRenewalClient()
+			if err != nil {
+				return err
+			}
+			_, err = vc.Auth().Token().RenewSelfWithContext(ctx, 0)
+			return err
+		},
+	}
+	for name, request := range tests {
+		t.Run(name, func(t *testing.T) {
+			c := newClient(t)
+			mu.Lock()
+			namespaces = nil
+			mu.Unlock()
+
+			require.NoError(t, request(context.Background(), c))
+
+			// the burst is exhausted, so the next request is throttled.
+			ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+			defer cancel()
+			assert.Error(t, request(ctx, c), "expected the request to be throttled")
+
+			mu.Lock()
+			defer mu.Unlock()
+			assert.Equal(t, []string{"tenant-1"}, namespaces)
+		})
+	}
+}
@@ -0,0 +1,234 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package vault
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/vault/api"
+	"k8s.io/apimachinery/pkg/types"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	secretsv1alpha1 "github.com/hashicorp/vault-secrets-operator/api/v1alpha1"
+	"github.com/hashicorp/vault-secrets-operator/internal/metrics"
+	"github.com/hashicorp/vault-secrets-operator/internal/vault/credentials"
+)
+
+// invalidWrappingTokenMessage is the error returned by Vault when a wrapping token
+// has already been unwrapped, or has expired.
+const invalidWrappingTokenMessage = "wrapping token is not valid or does not exist"
+
+// wrappingTokenConsumedErrorPrefix is the prefix of all WrappingTokenConsumedError messages.
+const wrappingTokenConsumedErrorPrefix = "response-wrapping token for VaultAuth"
+
+// wrappingTokenErrors tracks the WrappingTokenConsumedError of the last unwrap attempt of every
+// VaultAuth. The errors are only ever reported by the VaultAuth's reconciler, since it is the sole
+// owner of the VaultAuth's status.
+var wrappingTokenErrors = &wrappingTokenErrorRegistry{
+	errs: make(map[types.UID]map[types.UID]*WrappingTokenConsumedError),
+}
+
+type wrappingTokenErrorRegistry struct {
+	mu sync.Mutex
+	// errs maps the UID of a VaultAuth to the errors of each of its credential providers,
+	// keyed by the provider's UID, since the VaultAuth's consumers in different namespaces
+	// each have their own response-wrapping token.
+	errs map[types.UID]map[types.UID]*WrappingTokenConsumedError
+}
+
+func (r *wrappingTokenErrorRegistry) set(authUID, providerUID types.UID, err *WrappingTokenConsumedError) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.errs[authUID] == nil {
+		r.errs[authUID] = make(map[types.UID]*WrappingTokenConsumedError)
+	}
+	r.errs[authUID][providerUID] = err
+}
+
+func (r *wrappingTokenErrorRegistry) remove(authUID, providerUID types.UID) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.errs[authUID], providerUID)
+	if len(r.errs[authUID]) == 0 {
+		delete(r.errs, authUID)
+	}
+}
+
+func (r *wrappingTokenErrorRegistry) get(authUID types.UID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	uids := make([]string, 0, len(r.errs[authUID]))
+	for uid := range r.errs[authUID] {
+		uids = append(uids, string(uid))
+	}
+	// sorted for a stable error message.
+	sort.Strings(uids)
+
+	var errs []error
+	for _, uid := range uids {
+		errs = append(errs, r.errs[authUID][types.UID(uid)])
+	}
+	if len(errs) == 1 {
+		return errs[0]
+	}
+	return errors.Join(errs...)
+}
+
+func (r *wrappingTokenErrorRegistry) delete(authUID types.UID) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.errs, authUID)
+}
+
+// WrappingTokenConsumedError is returned when a VaultAuth's response-wrapping token
+// has already been consumed, or has expired, and its unwrapped value is unknown.
+type WrappingTokenConsumedError struct {
+	VaultAuth types.NamespacedName
+	Err       error
+}
+
+func (e *WrappingTokenConsumedError) Error() string {
+	return fmt.Sprintf("%s %s was already consumed or has expired, "+
+		"a new wrapping token must be provided: %s", wrappingTokenConsumedErrorPrefix, e.VaultAuth, e.Err)
+}
+
+func (e *WrappingTokenConsumedError) Unwrap() error {
+	return e.Err
+}
+
+// HasWrappedCredentials returns true if authObj's credential source is a response-wrapping token.
+func HasWrappedCredentials(authObj *secretsv1alpha1.VaultAuth) bool {
+	_, ok := wrappedCredsKey(authObj)
+	return ok
+}
+
+// GetWrappingTokenError returns the WrappingTokenConsumedError of the last attempt to unwrap
+// authObj's response-wrapping tokens, nil if they were successfully unwrapped.
+func GetWrappingTokenError(authObj *secretsv1alpha1.VaultAuth) error {
+	return wrappingTokenErrors.get(authObj.GetUID())
+}
+
+// DeleteWrappingTokenErrors removes the errors of authObj's response-wrapping tokens.
+// It should be called once the VaultAuth has been deleted.
+func DeleteWrappingTokenErrors(authObj *secretsv1alpha1.VaultAuth) {
+	wrappingTokenErrors.delete(authObj.GetUID())
+}
+
+// wrappedCredsKey returns the key in the credentials map that holds a wrapping token
+// for authObj, if its credential source is wrapped.
+func wrappedCredsKey(authObj *secretsv1alpha1.VaultAuth) (string, bool) {
+	switch authObj.Spec.Method {
+	case credentials.ProviderMethodAppRole:
+		if authObj.Spec.AppRole != nil && authObj.Spec.AppRole.Wrapped {
+			return "secret_id", true
+		}
+	case credentials.ProviderMethodJWT:
+		// only a JWT from the SecretKeyRef secret can be wrapped.
+		if jwt := authObj.Spec.JWT; jwt != nil && jwt.Wrapped && jwt.SecretKeyRef != nil &&
+			jwt.TokenFile == "" && jwt.ServiceAccount == "" {
+			return "jwt", true
+		}
+	case credentials.ProviderMethodToken:
+		if authObj.Spec.Token != nil && authObj.Spec.Token.Wrapped {
+			return credentials.TokenCredsKey, true
+		}
+	}
+	return "", false
+}
+
+// unwrapCreds replaces the response-wrapping token in creds with its unwrapped value.
+// It is a no-op if the VaultAuth's credential source is not wrapped.
+// A wrapping token can only ever be unwrapped once, and its unwrapped value is never retained
+// beyond the login, so a new wrapping token must be provided for every login.
+// It should be called from a write locked method only.
+func (c *defaultClient) unwrapCreds(ctx context.Context, creds map[string]any) error {
+	key, ok := wrappedCredsKey(c.authObj)
+	if !ok {
+		return nil
+	}
+
+	wrappingToken, ok := creds[key].(string)
+	if !ok || wrappingToken == "" {
+		return fmt.Errorf("no response-wrapping token found in the credentials")
+	}
+
+	var providerUID types.UID
+	if c.credentialProvider != nil {
+		providerUID = c.credentialProvider.GetUID()
+	}
+
+	resp, err := c.unwrap(ctx, wrappingToken)
+	if err != nil {
+		var respErr *api.ResponseError
+		if errors.As(err, &respErr) && respErr.StatusCode == http.StatusBadRequest &&
+			strings.Contains(strings.Join(respErr.Errors, " "), invalidWrappingTokenMessage) {
+			consumedErr := &WrappingTokenConsumedError{
+				VaultAuth: ctrlclient.ObjectKeyFromObject(c.authObj),
+				Err:       err,
+			}
+			wrappingTokenErrors.set(c.authObj.GetUID(), providerUID, consumedErr)
+			return consumedErr
+		}
+		return err
+	}
+	if resp == nil {
+		return fmt.Errorf("empty response from unwrap")
+	}
+
+	var value string
+	switch key {
+	case credentials.TokenCredsKey:
+		if resp.Auth != nil {
+			value = resp.Auth.ClientToken
+		}
+	default:
+		value, _ = resp.Data[key].(string)
+	}
+	if value == "" {
+		return fmt.Errorf("unwrapped response did not contain a value for %q", key)
+	}
+
+	wrappingTokenErrors.remove(c.authObj.GetUID(), providerUID)
+	creds[key] = value
+
+	return nil
+}
+
+func (c *defaultClient) unwrap(ctx context.Context, wrappingToken string) (*api.Secret, error) {
+	var err error
+	startTS := time.Now()
+	defer func() {
+		c.observeTime(startTS, metrics.OperationUnwrap)
+		c.incrementOperationCounter(metrics.OperationUnwrap, err)
+	}()
+
+	var release func()
+	release, err = c.throttle(ctx, metrics.OperationUnwrap)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	// UnwrapWithContext sets the client's token to the wrapping token when
+	// none is set, so it must be cleared afterwards.
+	hasToken := c.client.Token() != ""
+	var secret *api.Secret
+	secret, err = c.client.Logical().UnwrapWithContext(ctx, wrappingToken)
+	if !hasToken {
+		c.client.ClearToken()
+	}
+
+	return secret, err
+}
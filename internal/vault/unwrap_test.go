@@ -0,0 +1,297 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package vault
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/vault/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	secretsv1alpha1 "github.com/hashicorp/vault-secrets-operator/api/v1alpha1"
+	"github.com/hashicorp/vault-secrets-operator/internal/vault/credentials"
+)
+
+func Test_defaultClient_unwrapCreds(t *testing.T) {
+	// wrapping tokens can only be unwrapped once.
+	var mu sync.Mutex
+	consumed := map[string]bool{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/sys/wrapping/unwrap" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		token := r.Header.Get("X-Vault-Token")
+		var body map[string]string
+		if err := json.NewDecoder(r.Body).Decode(&body); err == nil && body["token"] != "" {
+			token = body["token"]
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		if consumed[token] || token == "expired" {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprintf(w, `{"errors":[%q]}`, invalidWrappingTokenMessage)
+			return
+		}
+		consumed[token] = true
+
+		switch token {
+		case "wrapped-secret-id":
+			fmt.Fprint(w, `{"data":{"secret_id":"secret-id","secret_id_accessor":"accessor"}}`)
+		case "wrapped-token":
+			fmt.Fprint(w, `{"auth":{"client_token":"client-token"}}`)
+		case "wrapped-jwt":
+			fmt.Fprint(w, `{"data":{"jwt":"jwt-1"}}`)
+		default:
+			w.WriteHeader(http.StatusForbidden)
+			fmt.Fprint(w, `{"errors":["permission denied"]}`)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	approle := secretsv1alpha1.VaultAuthSpec{
+		Method: credentials.ProviderMethodAppRole,
+		AppRole: &secretsv1alpha1.VaultAuthConfigAppRole{
+			Wrapped: true,
+		},
+	}
+	token := secretsv1alpha1.VaultAuthSpec{
+		Method: credentials.ProviderMethodToken,
+		Token: &secretsv1alpha1.VaultAuthConfigToken{
+			Wrapped: true,
+		},
+	}
+
+	jwt := secretsv1alpha1.VaultAuthSpec{
+		Method: credentials.ProviderMethodJWT,
+		JWT: &secretsv1alpha1.VaultAuthConfigJWT{
+			Role: "role-1",
+			SecretKeyRef: &secretsv1alpha1.SecretKeySelector{
+				Name: "jwt",
+				Key:  "token",
+			},
+			Wrapped: true,
+		},
+	}
+
+	tests := []struct {
+		name            string
+		spec            secretsv1alpha1.VaultAuthSpec
+		creds           []map[string]any
+		want            map[string]any
+		wantErr         assert.ErrorAssertionFunc
+		wantToken       string
+		wantConsumedErr bool
+	}{
+		{
+			name: "not-wrapped",
+			spec: secretsv1alpha1.VaultAuthSpec{
+				Method: credentials.ProviderMethodAppRole,
+				AppRole: &secretsv1alpha1.VaultAuthConfigAppRole{
+					RoleID: "role-id",
+				},
+			},
+			creds: []map[string]any{
+				{"role_id": "role-id", "secret_id": "secret-id"},
+			},
+			want:    map[string]any{"role_id": "role-id", "secret_id": "secret-id"},
+			wantErr: assert.NoError,
+		},
+		{
+			name: "approle-secret-id",
+			spec: approle,
+			creds: []map[string]any{
+				{"role_id": "role-id", "secret_id": "wrapped-secret-id"},
+			},
+			want:    map[string]any{"role_id": "role-id", "secret_id": "secret-id"},
+			wantErr: assert.NoError,
+		},
+		{
+			// the unwrapped value is never retained, so a wrapping token is only valid for a single login.
+			name: "approle-secret-id-reused",
+			spec: approle,
+			creds: []map[string]any{
+				{"role_id": "role-id", "secret_id": "wrapped-secret-id"},
+				{"role_id": "role-id", "secret_id": "wrapped-secret-id"},
+			},
+			wantErr: func(t assert.TestingT, err error, i ...interface{}) bool {
+				var consumedErr *WrappingTokenConsumedError
+				return assert.ErrorAs(t, err, &consumedErr, i...)
+			},
+			wantConsumedErr: true,
+		},
+		{
+			name: "token",
+			spec: token,
+			creds: []map[string]any{
+				{credentials.TokenCredsKey: "wrapped-token"},
+			},
+			want:    map[string]any{credentials.TokenCredsKey: "client-token"},
+			wantErr: assert.NoError,
+		},
+		{
+			name: "jwt",
+			spec: jwt,
+			creds: []map[string]any{
+				{"role": "role-1", "jwt": "wrapped-jwt"},
+			},
+			want:    map[string]any{"role": "role-1", "jwt": "jwt-1"},
+			wantErr: assert.NoError,
+		},
+		{
+			name: "jwt-service-account",
+			spec: secretsv1alpha1.VaultAuthSpec{
+				Method: credentials.ProviderMethodJWT,
+				JWT: &secretsv1alpha1.VaultAuthConfigJWT{
+					Role:           "role-1",
+					SecretKeyRef:   jwt.JWT.SecretKeyRef,
+					ServiceAccount: "default",
+					Wrapped:        true,
+				},
+			},
+			creds: []map[string]any{
+				{"role": "role-1", "jwt": "sa-token"},
+			},
+			want:    map[string]any{"role": "role-1", "jwt": "sa-token"},
+			wantErr: assert.NoError,
+		},
+		{
+			name:      "with-client-token-set",
+			spec:      approle,
+			wantToken: "existing-token",
+			creds: []map[string]any{
+				{"role_id": "role-id", "secret_id": "wrapped-secret-id"},
+			},
+			want:    map[string]any{"role_id": "role-id", "secret_id": "secret-id"},
+			wantErr: assert.NoError,
+		},
+		{
+			name: "consumed",
+			spec: approle,
+			creds: []map[string]any{
+				{"role_id": "role-id", "secret_id": "expired"},
+			},
+			wantErr: func(t assert.TestingT, err error, i ...interface{}) bool {
+				var consumedErr *WrappingTokenConsumedError
+				return assert.ErrorAs(t, err, &consumedErr, i...) &&
+					assert.Equal(t, types.NamespacedName{Namespace: "tenant-1", Name: "auth-1"},
+						consumedErr.VaultAuth, i...)
+			},
+			wantConsumedErr: true,
+		},
+		{
+			name: "consumed-then-replaced",
+			spec: approle,
+			creds: []map[string]any{
+				{"role_id": "role-id", "secret_id": "expired"},
+				{"role_id": "role-id", "secret_id": "wrapped-secret-id"},
+			},
+			want:    map[string]any{"role_id": "role-id", "secret_id": "secret-id"},
+			wantErr: assert.NoError,
+		},
+		{
+			name: "invalid-token",
+			spec: approle,
+			creds: []map[string]any{
+				{"role_id": "role-id", "secret_id": "invalid"},
+			},
+			wantErr: func(t assert.TestingT, err error, i ...interface{}) bool {
+				var consumedErr *WrappingTokenConsumedError
+				return assert.Error(t, err, i...) && assert.False(t, errors.As(err, &consumedErr), i...)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wrappingTokenErrors.delete("uid-1")
+			mu.Lock()
+			consumed = map[string]bool{}
+			mu.Unlock()
+
+			config := api.DefaultConfig()
+			config.Address = server.URL
+			config.MaxRetries = 0
+			vc, err := api.NewClient(config)
+			require.NoError(t, err)
+			vc.SetToken(tt.wantToken)
+
+			c := &defaultClient{
+				client: vc,
+				authObj: &secretsv1alpha1.VaultAuth{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "auth-1",
+						Namespace: "tenant-1",
+						UID:       "uid-1",
+					},
+					Spec: tt.spec,
+				},
+				connObj: &secretsv1alpha1.VaultConnection{},
+			}
+
+			var got map[string]any
+			for _, creds := range tt.creds {
+				err = c.unwrapCreds(context.Background(), creds)
+				got = creds
+			}
+			// the error of the last attempt is reported by the VaultAuth's reconciler.
+			if tt.wantConsumedErr {
+				assert.Equal(t, err, GetWrappingTokenError(c.authObj))
+			} else {
+				assert.NoError(t, GetWrappingTokenError(c.authObj))
+			}
+			if !tt.wantErr(t, err, fmt.Sprintf("unwrapCreds(%v)", tt.creds)) {
+				return
+			}
+			if err == nil {
+				assert.Equalf(t, tt.want, got, "unwrapCreds(%v)", tt.creds)
+			}
+			// the client's token must never be left set to the wrapping token.
+			assert.Equal(t, tt.wantToken, vc.Token())
+		})
+	}
+}
+
+func Test_wrappingTokenErrorRegistry(t *testing.T) {
+	r := &wrappingTokenErrorRegistry{
+		errs: make(map[types.UID]map[types.UID]*WrappingTokenConsumedError),
+	}
+	errFor := func(ns string) *WrappingTokenConsumedError {
+		return &WrappingTokenConsumedError{
+			VaultAuth: types.NamespacedName{Namespace: ns, Name: "auth-1"},
+			Err:       errors.New(invalidWrappingTokenMessage),
+		}
+	}
+
+	assert.NoError(t, r.get("auth-1"))
+
+	// each of the VaultAuth's providers has its own wrapping token.
+	r.set("auth-1", "provider-2", errFor("tenant-2"))
+	r.set("auth-1", "provider-1", errFor("tenant-1"))
+	r.set("auth-2", "provider-3", errFor("tenant-3"))
+	assert.Equal(t, errors.Join(errFor("tenant-1"), errFor("tenant-2")), r.get("auth-1"))
+
+	r.remove("auth-1", "provider-2")
+	assert.Equal(t, errFor("tenant-1"), r.get("auth-1"))
+	r.remove("auth-1", "provider-1")
+	assert.NoError(t, r.get("auth-1"))
+	assert.NotContains(t, r.errs, types.UID("auth-1"))
+
+	// all of a deleted VaultAuth's errors are removed.
+	r.set("auth-1", "provider-1", errFor("tenant-1"))
+	r.delete("auth-1")
+	assert.NoError(t, r.get("auth-1"))
+	assert.Equal(t, errFor("tenant-3"), r.get("auth-2"))
+}
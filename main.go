@@ -10,6 +10,7 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"strings"
 	"time"
 
 	"gopkg.in/yaml.v3"
@@ -30,6 +31,7 @@ import (
 	"github.com/hashicorp/vault-secrets-operator/controllers"
 	"github.com/hashicorp/vault-secrets-operator/internal/metrics"
 	vclient "github.com/hashicorp/vault-secrets-operator/internal/vault"
+	"github.com/hashicorp/vault-secrets-operator/internal/vault/credentials"
 	"github.com/hashicorp/vault-secrets-operator/internal/version"
 	//+kubebuilder:scaffold:imports
 )
@@ -62,6 +64,13 @@ func main() {
 	var printVersion bool
 	var outputFormat string
 	var finalizerCleanup bool
+	var tokenRequestReuseFraction float64
+	var tokenFileDirs string
+	var awsSTSEndpoints string
+	var gcpMetadataEndpoint string
+	var gcpIAMCredentialsEndpoint string
+	var azureIMDSEndpoint string
+	var azureAuthorityHost string
 	flag.BoolVar(&printVersion, "version", false, "Print the operator version information")
 	flag.StringVar(&outputFormat, "output", "", "Output format for the operator version information (yaml or json)")
 	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8080", "The address the metric endpoint binds to.")
@@ -78,6 +87,23 @@ func main() {
 	flag.IntVar(&vdsOptions.MaxConcurrentReconciles, "max-concurrent-reconciles-vds", 100,
 		"Maximum number of concurrent reconciles for the VaultDynamicSecrets controller.")
 	flag.BoolVar(&finalizerCleanup, "finalizer-cleanup", false, "Remove finalizers from all CRs in preparation for shutdown.")
+	flag.Float64Var(&tokenRequestReuseFraction, "token-request-reuse-fraction", credentials.DefaultTokenRequestReuseFraction,
+		"Fraction of a requested ServiceAccount token's lifetime during which it is reused for Vault logins. "+
+			"Setting this to 0 disables reuse.")
+	flag.StringVar(&tokenFileDirs, "allowed-token-file-dirs", "",
+		"Comma separated list of the directories that a VaultAuth's tokenFile must be located in. "+
+			"Any token in these directories can be used by all VaultAuths. tokenFile is disabled if unset.")
+	flag.StringVar(&awsSTSEndpoints, "allowed-aws-sts-endpoints", "",
+		"Comma separated list of the STS endpoints, in addition to the public AWS STS endpoints, "+
+			"that a VaultAuth's aws.stsEndpoint may be set to, e.g. STS VPC endpoints.")
+	flag.StringVar(&gcpMetadataEndpoint, "gcp-metadata-endpoint", credentials.GCPDefaultMetadataEndpoint,
+		"The URL of the GCP metadata server used for GCP Workload Identity.")
+	flag.StringVar(&gcpIAMCredentialsEndpoint, "gcp-iam-credentials-endpoint", credentials.GCPDefaultIAMCredentialsEndpoint,
+		"The URL of the GCP IAM Credentials API used for GCP Workload Identity.")
+	flag.StringVar(&azureIMDSEndpoint, "azure-imds-endpoint", credentials.AzureDefaultIMDSEndpoint,
+		"The URL of the Azure IMDS managed identity token endpoint.")
+	flag.StringVar(&azureAuthorityHost, "azure-authority-host", credentials.AzureDefaultAuthorityHost,
+		"The URL of the Microsoft Entra ID authority host used for Azure workload identity.")
 	opts := zap.Options{
 		Development: true,
 	}
@@ -173,6 +199,27 @@ func main() {
 			metrics.NewBuildInfoGauge(versionInfo),
 		)
 		vclient.MustRegisterClientMetrics(cfc.MetricsRegistry)
+		credentials.MustRegisterMetrics(cfc.MetricsRegistry)
+	}
+	if err := credentials.SetTokenRequestReuseFraction(tokenRequestReuseFraction); err != nil {
+		setupLog.Error(err, "Invalid token request reuse fraction")
+		os.Exit(1)
+	}
+	if err := credentials.SetTokenFileDirs(strings.Split(tokenFileDirs, ",")); err != nil {
+		setupLog.Error(err, "Invalid allowed token file directories")
+		os.Exit(1)
+	}
+	if err := credentials.SetAWSSTSEndpoints(strings.Split(awsSTSEndpoints, ",")); err != nil {
+		setupLog.Error(err, "Invalid allowed AWS STS endpoints")
+		os.Exit(1)
+	}
+	if err := credentials.SetGCPEndpoints(gcpMetadataEndpoint, gcpIAMCredentialsEndpoint); err != nil {
+		setupLog.Error(err, "Invalid GCP endpoints")
+		os.Exit(1)
+	}
+	if err := credentials.SetAzureEndpoints(azureIMDSEndpoint, azureAuthorityHost); err != nil {
+		setupLog.Error(err, "Invalid Azure endpoints")
+		os.Exit(1)
 	}
 	var clientFactory vclient.CachingClientFactory
 	{
@@ -254,6 +301,15 @@ func main() {
 		setupLog.Error(err, "Unable to create controller", "controller", "VaultDynamicSecret")
 		os.Exit(1)
 	}
+	if err = (&controllers.VaultSecretBundleReconciler{
+		Client:        mgr.GetClient(),
+		Scheme:        mgr.GetScheme(),
+		Recorder:      mgr.GetEventRecorderFor("VaultSecretBundle"),
+		ClientFactory: clientFactory,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "Unable to create controller", "controller", "VaultSecretBundle")
+		os.Exit(1)
+	}
 	//+kubebuilder:scaffold:builder
 
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
@@ -281,7 +281,9 @@ func checkTLSFields(secret *corev1.Secret) (ok bool, err error) {
 }
 
 type authMethodsK8sOutputs struct {
-	AuthRole string `json:"auth_role"`
+	AuthRole          string `json:"auth_role"`
+	AppRoleRoleID     string `json:"approle_role_id"`
+	AppRoleSecretName string `json:"approle_secret_name"`
 }
 
 type dynamicK8SOutputs struct {
@@ -115,6 +115,35 @@ resource "vault_jwt_auth_backend_role" "dev" {
   token_policies  = [vault_policy.default.name]
 }
 
+# approle auth config
+resource "vault_auth_backend" "approle" {
+  namespace = local.namespace
+  type      = "approle"
+}
+
+resource "vault_approle_auth_backend_role" "role" {
+  namespace      = vault_auth_backend.approle.namespace
+  backend        = vault_auth_backend.approle.path
+  role_name      = var.auth_role
+  token_policies = [vault_policy.default.name]
+}
+
+resource "vault_approle_auth_backend_role_secret_id" "id" {
+  namespace = vault_auth_backend.approle.namespace
+  backend   = vault_auth_backend.approle.path
+  role_name = vault_approle_auth_backend_role.role.role_name
+}
+
+resource "kubernetes_secret" "approle-secret-id" {
+  metadata {
+    namespace = kubernetes_namespace.tenant-1.metadata[0].name
+    name      = "approle-secret-id"
+  }
+  data = {
+    id = vault_approle_auth_backend_role_secret_id.id.secret_id
+  }
+}
+
 resource "helm_release" "vault-secrets-operator" {
   name             = "test"
   namespace        = var.operator_namespace
@@ -3,4 +3,12 @@
 
 output "auth_role" {
   value = var.auth_role
-}
\ No newline at end of file
+}
+
+output "approle_role_id" {
+  value = vault_approle_auth_backend_role.role.role_id
+}
+
+output "approle_secret_name" {
+  value = kubernetes_secret.approle-secret-id.metadata[0].name
+}
@@ -158,6 +158,24 @@ func TestVaultAuthMethods(t *testing.T) {
 				},
 			},
 		},
+		{
+			ObjectMeta: v1.ObjectMeta{
+				Name:      "vaultauth-test-approle",
+				Namespace: testK8sNamespace,
+			},
+			Spec: secretsv1alpha1.VaultAuthSpec{
+				Namespace: testVaultNamespace,
+				Method:    "appRole",
+				Mount:     "approle",
+				AppRole: &secretsv1alpha1.VaultAuthConfigAppRole{
+					RoleID: outputs.AppRoleRoleID,
+					SecretKeyRef: secretsv1alpha1.SecretKeySelector{
+						Name: outputs.AppRoleSecretName,
+						Key:  "id",
+					},
+				},
+			},
+		},
 	}
 	// Apply all the Auth Methods
 	for _, a := range auths {
@@ -51,6 +51,8 @@ load _helpers
         --set 'defaultVaultConnection.address=https://foo.com:8200' \
         --set 'defaultVaultConnection.skipTLSVerify=true' \
         --set 'defaultVaultConnection.caCertSecret=foo' \
+        --set 'defaultVaultConnection.caCertConfigMap=bar' \
+        --set 'defaultVaultConnection.caCertConfigMapKey=trust-bundle.pem' \
         --set 'defaultVaultConnection.tlsServerName=foo.com' \
         --set 'defaultVaultConnection.headers=foo: bar' \
         . | tee /dev/stderr)
@@ -65,6 +67,10 @@ load _helpers
      [ "${actual}" = "true" ]
     actual=$(echo "$object" | yq '.spec.caCertSecretRef' | tee /dev/stderr)
      [ "${actual}" = "foo" ]
+    actual=$(echo "$object" | yq '.spec.caCertConfigMapRef' | tee /dev/stderr)
+     [ "${actual}" = "bar" ]
+    actual=$(echo "$object" | yq '.spec.caCertConfigMapKey' | tee /dev/stderr)
+     [ "${actual}" = "trust-bundle.pem" ]
     actual=$(echo "$object" | yq '.spec.tlsServerName' | tee /dev/stderr)
      [ "${actual}" = "foo.com" ]
     actual=$(echo "$object" | yq '.spec.headers.foo' | tee /dev/stderr)